@@ -0,0 +1,40 @@
+package beatmap_test
+
+import (
+	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/testutil"
+	"testing"
+)
+
+func TestGoldenObjects(t *testing.T) {
+	bMap := testutil.LoadBeatMap(t, "golden", "golden.osu")
+
+	golden := &testutil.Golden{}
+
+	for _, o := range bMap.HitObjects {
+		data := o.GetBasicData()
+
+		switch obj := o.(type) {
+		case *objects.Circle:
+			golden.Printf("circle %d pos=%.3f,%.3f stack=%d combo=%d/%d", data.StartTime, data.StartPos.X, data.StartPos.Y, data.StackIndex, data.ComboSet, data.ComboNumber)
+		case *objects.Spinner:
+			golden.Printf("spinner %d-%d", data.StartTime, data.EndTime)
+		case *objects.Slider:
+			golden.Printf("slider %d-%d start=%.3f,%.3f end=%.3f,%.3f stack=%d combo=%d/%d", data.StartTime, data.EndTime, data.StartPos.X, data.StartPos.Y, data.EndPos.X, data.EndPos.Y, data.StackIndex, data.ComboSet, data.ComboNumber)
+
+			// Path sampled at fixed fractions of slider's duration
+			for i := 0; i <= 20; i++ {
+				time := data.StartTime + (data.EndTime-data.StartTime)*int64(i)/20
+				pos := obj.GetPointAt(time)
+
+				golden.Printf("\tpath %d %.3f,%.3f", time, pos.X, pos.Y)
+			}
+
+			for _, p := range obj.ScorePoints {
+				golden.Printf("\tscore point %d %.3f,%.3f reverse=%t", p.Time, p.Pos.X, p.Pos.Y, p.IsReverse)
+			}
+		}
+	}
+
+	golden.Check(t, "objects")
+}
@@ -21,6 +21,7 @@ type BaseObject interface {
 type Renderable interface {
 	Draw(time int64, color color2.Color, batch *batch.QuadBatch) bool
 	DrawApproach(time int64, color color2.Color, batch *batch.QuadBatch)
+	ReloadTextures()
 }
 
 type basicData struct {
@@ -146,22 +146,12 @@ func (circle *Circle) SetDifficulty(diff *difficulty.Difficulty) {
 
 	circle.textFade = animation.NewGlider(0)
 
-	defaul := skin.GetTexture(defaultCircleName + "circle")
-	named := skin.GetTexture(circle.textureName + "circle")
-
-	name := circle.textureName + "circle"
+	circle.hitCircle = sprite.NewSpriteSingle(nil, 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
+	circle.hitCircleOverlay = sprite.NewSpriteSingle(nil, 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
+	circle.approachCircle = sprite.NewSpriteSingle(nil, 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
+	circle.reverseArrow = sprite.NewSpriteSingle(nil, 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
 
-	if named == nil || skin.GetMostSpecific(named, defaul) == defaul {
-		name = defaultCircleName + "circle"
-	}
-
-	circle.hitCircleTexture = skin.GetTexture(name)
-	circle.fullTexture = skin.GetTexture("hitcircle-full")
-
-	circle.hitCircle = sprite.NewSpriteSingle(circle.hitCircleTexture, 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
-	circle.hitCircleOverlay = sprite.NewSpriteSingle(skin.GetTextureSource(name+"overlay", skin.GetSource(name)), 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
-	circle.approachCircle = sprite.NewSpriteSingle(skin.GetTexture("approachcircle"), 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
-	circle.reverseArrow = sprite.NewSpriteSingle(skin.GetTexture("reversearrow"), 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
+	circle.ReloadTextures()
 
 	circle.sprites = append(circle.sprites, circle.hitCircle, circle.hitCircleOverlay, circle.approachCircle, circle.reverseArrow)
 
@@ -217,6 +207,26 @@ func (circle *Circle) SetDifficulty(diff *difficulty.Difficulty) {
 	}
 }
 
+// ReloadTextures fetches textures from the current skin, state of the circle stays untouched.
+func (circle *Circle) ReloadTextures() {
+	defaul := skin.GetTexture(defaultCircleName + "circle")
+	named := skin.GetTexture(circle.textureName + "circle")
+
+	name := circle.textureName + "circle"
+
+	if named == nil || skin.GetMostSpecific(named, defaul) == defaul {
+		name = defaultCircleName + "circle"
+	}
+
+	circle.hitCircleTexture = skin.GetTexture(name)
+	circle.fullTexture = skin.GetTexture("hitcircle-full")
+
+	circle.hitCircle.SetTextures([]*texture.TextureRegion{circle.hitCircleTexture}, 0)
+	circle.hitCircleOverlay.SetTextures([]*texture.TextureRegion{skin.GetTextureSource(name+"overlay", skin.GetSource(name))}, 0)
+	circle.approachCircle.SetTextures([]*texture.TextureRegion{skin.GetTexture("approachcircle")}, 0)
+	circle.reverseArrow.SetTextures([]*texture.TextureRegion{skin.GetTexture("reversearrow")}, 0)
+}
+
 func (circle *Circle) Arm(clicked bool, time int64) {
 	circle.hitCircle.ClearTransformations()
 	circle.hitCircleOverlay.ClearTransformations()
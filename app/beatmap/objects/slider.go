@@ -395,21 +395,18 @@ func (slider *Slider) SetDifficulty(diff *difficulty.Difficulty) {
 
 	slider.edges = append(slider.edges, slider.startCircle)
 
-	sixty := 1000.0 / 60
-	frameDelay := math.Max(150/slider.Timings.GetVelocity(slider.TPoint)*sixty, sixty)
-
-	slider.ball = sprite.NewAnimation(skin.GetFrames("sliderb", false), frameDelay, true, 0.0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
+	slider.ball = sprite.NewAnimation(nil, 0, true, 0.0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
 
 	if len(slider.scorePath) > 0 {
 		angle := slider.scorePath[0].Line.GetStartAngle()
 		slider.ball.SetVFlip(angle > -math32.Pi/2 && angle < math32.Pi/2)
 	}
 
-	followerFrames := skin.GetFrames("sliderfollowcircle", true)
-
-	slider.follower = sprite.NewAnimation(followerFrames, 1000.0/float64(len(followerFrames)), true, 0.0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
+	slider.follower = sprite.NewAnimation(nil, 0, true, 0.0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
 	slider.follower.SetAlpha(0.0)
 
+	slider.reloadBallTextures()
+
 	for i := int64(1); i <= slider.repeat; i++ {
 		appearTime := slider.objData.StartTime - int64(slider.diff.Preempt)
 		circleTime := slider.objData.StartTime + int64(slider.partLen*float64(i))
@@ -459,6 +456,25 @@ func (slider *Slider) SetDifficulty(diff *difficulty.Difficulty) {
 	slider.body = sliderrenderer.NewBody(slider.multiCurve, float32(slider.diff.CircleRadius))
 }
 
+// ReloadTextures fetches textures from the current skin, state of the slider stays untouched.
+func (slider *Slider) ReloadTextures() {
+	slider.reloadBallTextures()
+
+	for _, edge := range slider.edges {
+		edge.ReloadTextures()
+	}
+}
+
+func (slider *Slider) reloadBallTextures() {
+	sixty := 1000.0 / 60
+	frameDelay := math.Max(150/slider.Timings.GetVelocity(slider.TPoint)*sixty, sixty)
+
+	slider.ball.SetTextures(skin.GetFrames("sliderb", false), frameDelay)
+
+	followerFrames := skin.GetFrames("sliderfollowcircle", true)
+	slider.follower.SetTextures(followerFrames, 1000.0/float64(len(followerFrames)))
+}
+
 func (slider *Slider) IsRetarded() bool {
 	return len(slider.scorePath) == 0 || slider.objData.StartTime == slider.objData.EndTime
 }
@@ -709,8 +725,14 @@ func (slider *Slider) DrawBody(time int64, bodyColor, innerBorder, outerBorder c
 			baseTrack = skin.GetInfo().ComboColors[int(slider.objData.ComboSet)%len(skin.GetInfo().ComboColors)]
 		}
 
-		bodyOuter = baseTrack.Shade2(-0.1)
-		bodyInner = baseTrack.Shade2(0.5)
+		if skin.GetInfo().SliderStyle == 1 {
+			// old style sliders have a flat body
+			bodyOuter = baseTrack
+			bodyInner = baseTrack
+		} else {
+			bodyOuter = baseTrack.Shade2(-0.1)
+			bodyInner = baseTrack.Shade2(0.5)
+		}
 	} else {
 		if settings.Objects.Colors.UseComboColors {
 			cHSV := settings.Objects.Colors.ComboColors[int(slider.objData.ComboSet)%len(settings.Objects.Colors.ComboColors)]
@@ -6,6 +6,7 @@ import (
 	"github.com/wieku/danser-go/app/audio"
 	"github.com/wieku/danser-go/app/beatmap/difficulty"
 	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/graphics"
 	"github.com/wieku/danser-go/app/settings"
 	"github.com/wieku/danser-go/app/skin"
 	"github.com/wieku/danser-go/framework/bass"
@@ -29,12 +30,14 @@ var spinnerBlue = color2.Color{R: 0.05, G: 0.5, B: 1.0, A: 1}
 type Spinner struct {
 	objData  *basicData
 	Timings  *Timings
+	diff     *difficulty.Difficulty
 	sample   int
 	rad      float32
 	pos      vector.Vector2f
 	fade     *animation.Glider
 	lastTime int64
 	rpm      float64
+	random   *rand.Rand
 
 	spinnerbonus *bass.Sample
 	loopSample   bass.SubSample
@@ -96,9 +99,12 @@ func (spinner *Spinner) SetTiming(timings *Timings) {
 func (spinner *Spinner) UpdateStacking() {}
 
 func (spinner *Spinner) SetDifficulty(diff *difficulty.Difficulty) {
+	spinner.diff = diff
 	spinner.ScaledHeight = 768
 	spinner.ScaledWidth = settings.Graphics.GetAspectRatio() * spinner.ScaledHeight
 
+	spinner.random = bmath.NewRandom(settings.SEED, fmt.Sprintf("spinner%d", spinner.objData.StartTime))
+
 	spinner.fade = animation.NewGlider(0)
 	spinner.fade.AddEvent(float64(spinner.objData.StartTime)-difficulty.HitFadeIn, float64(spinner.objData.StartTime), 1)
 	spinner.fade.AddEvent(float64(spinner.objData.EndTime), float64(spinner.objData.EndTime)+difficulty.HitFadeOut, 0)
@@ -106,7 +112,9 @@ func (spinner *Spinner) SetDifficulty(diff *difficulty.Difficulty) {
 	spinner.sprites = sprite.NewSpriteManager()
 	spinner.frontSprites = sprite.NewSpriteManager()
 
-	spinner.newStyle = skin.GetTexture("spinner-background") == nil
+	// old style is used only if the skin provides it, 2.x skins prefer new one if it's available
+	hasBackground := skin.GetTexture("spinner-background") != nil
+	spinner.newStyle = !hasBackground || (skin.GetInfo().Version >= 2 && skin.GetSource("spinner-top") == skin.SKIN)
 
 	if spinner.newStyle {
 		spinner.glow = sprite.NewSpriteSingle(skin.GetTexture("spinner-glow"), 0.0, spinner.objData.StartPos.Copy64(), bmath.Origin.Centre)
@@ -129,6 +137,7 @@ func (spinner *Spinner) SetDifficulty(diff *difficulty.Difficulty) {
 
 	} else {
 		spinner.background = sprite.NewSpriteSingle(skin.GetTexture("spinner-background"), 0.0, vector.NewVec2d(spinner.ScaledWidth/2, 46.5+350.4), bmath.Origin.Centre)
+		spinner.background.SetColor(skin.GetInfo().SpinnerBackground)
 		spinner.metre = sprite.NewSpriteSingle(skin.GetTexture("spinner-metre"), 1.0, vector.NewVec2d(spinner.ScaledWidth/2-512, 46.5), bmath.Origin.TopLeft)
 		spinner.metre.SetCutOrigin(bmath.Origin.BottomCentre)
 
@@ -162,6 +171,19 @@ func (spinner *Spinner) SetDifficulty(diff *difficulty.Difficulty) {
 	//spinner.frontSprites.Add(spinner.rpmBg)
 }
 
+// ReloadTextures rebuilds spinner's sprites from the current skin, style may change with it.
+func (spinner *Spinner) ReloadTextures() {
+	rotation := spinner.rad
+	completion := spinner.completion
+	bonus := spinner.bonus
+
+	spinner.SetDifficulty(spinner.diff)
+
+	spinner.SetRotation(float64(rotation))
+	spinner.UpdateCompletion(completion)
+	spinner.bonus = bonus
+}
+
 func (spinner *Spinner) Update(time int64) bool {
 	spinner.fade.Update(float64(time))
 
@@ -240,6 +262,23 @@ func (spinner *Spinner) Draw(time int64, color color2.Color, batch *batch.QuadBa
 	scale := batch.GetScale()
 	batch.SetScale(1, 1)
 
+	if spinner.newStyle && skin.GetInfo().SpinnerFadePlayfield {
+		oldCamera := batch.Projection
+
+		batch.SetCamera(scaledOrtho)
+
+		batch.SetColor(0, 0, 0, alpha)
+		batch.SetTranslation(vector.NewVec2d(spinner.ScaledWidth/2, spinner.ScaledHeight/2))
+		batch.SetSubScale(spinner.ScaledWidth/2+float64(overdrawX), spinner.ScaledHeight/2+float64(overdrawY))
+		batch.DrawUnit(graphics.Pixel.GetRegion())
+
+		batch.SetSubScale(1, 1)
+		batch.SetTranslation(vector.NewVec2d(0, 0))
+		batch.SetColor(1, 1, 1, alpha)
+
+		batch.SetCamera(oldCamera)
+	}
+
 	if !spinner.newStyle {
 		oldCamera := batch.Projection
 
@@ -352,7 +391,7 @@ func (spinner *Spinner) UpdateCompletion(completion float64) {
 	} else if spinner.metre != nil {
 		bars := int(math.Min(0.99, completion) * 10)
 
-		if skin.GetInfo().SpinnerNoBlink || rand.Float64() < math.Mod(completion*10, 1) {
+		if skin.GetInfo().SpinnerNoBlink || spinner.random.Float64() < math.Mod(completion*10, 1) {
 			bars++
 		}
 
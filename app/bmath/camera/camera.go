@@ -4,6 +4,9 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/wieku/danser-go/app/settings"
 	"github.com/wieku/danser-go/framework/math/vector"
+	"log"
+	"math"
+	"strings"
 )
 
 const OsuWidth = 512.0
@@ -28,6 +31,8 @@ type Camera struct {
 
 	rebuildCache bool
 	cache        []mgl32.Mat4
+	cacheMode    string
+	warnedMode   string
 }
 
 func NewCamera() *Camera {
@@ -140,19 +145,79 @@ func (camera *Camera) Update() {
 }
 
 func (camera *Camera) GenRotated(rotations int, rotOffset float64) []mgl32.Mat4 {
+	return camera.genCopies(rotations, "rotate", rotOffset, nil)
+}
+
+// GenSymmetric returns view matrices of playfield copies laid out according to settings.Playfield.Symmetry.
+func (camera *Camera) GenSymmetric(copies int) []mgl32.Mat4 {
+	mode := strings.ToLower(settings.Playfield.Symmetry.Mode)
 
-	if len(camera.cache) != rotations || camera.rebuildCache {
-		if len(camera.cache) != rotations {
-			camera.cache = make([]mgl32.Mat4, rotations)
+	switch mode {
+	case "rotate", "mirror-horizontal", "mirror-vertical", "mirror-both", "kaleidoscope":
+	default:
+		if camera.warnedMode != mode {
+			log.Println("Unknown symmetry mode:", settings.Playfield.Symmetry.Mode, "- using rotate")
+			camera.warnedMode = mode
+		}
+
+		mode = "rotate"
+	}
+
+	return camera.genCopies(copies, mode, -2*math.Pi/float64(copies), settings.Playfield.Symmetry.Layout)
+}
+
+func (camera *Camera) genCopies(copies int, mode string, rotOffset float64, layout []*settings.PlayfieldCopy) []mgl32.Mat4 {
+	if len(camera.cache) != copies || camera.rebuildCache || camera.cacheMode != mode {
+		if len(camera.cache) != copies {
+			camera.cache = make([]mgl32.Mat4, copies)
 		}
 
 		pos := mgl32.Translate3D(camera.position.X32(), camera.position.Y32(), 0)
 		view := mgl32.HomogRotate3DZ(float32(camera.rotation)).Mul4(mgl32.Scale3D(camera.scale.X32(), camera.scale.Y32(), 1)).Mul4(mgl32.Translate3D(camera.origin.X32(), camera.origin.Y32(), 0))
 
-		for i := 0; i < rotations; i++ {
-			camera.cache[i] = camera.projection.Mul4(pos).Mul4(mgl32.HomogRotate3DZ(float32(i) * float32(rotOffset))).Mul4(view)
+		for i := 0; i < copies; i++ {
+			transform := mgl32.Ident4()
+
+			switch mode {
+			case "rotate":
+				transform = mgl32.HomogRotate3DZ(float32(i) * float32(rotOffset))
+			case "mirror-horizontal":
+				if i%2 == 1 {
+					transform = mgl32.Scale3D(-1, 1, 1)
+				}
+			case "mirror-vertical":
+				if i%2 == 1 {
+					transform = mgl32.Scale3D(1, -1, 1)
+				}
+			case "mirror-both":
+				transform = [4]mgl32.Mat4{mgl32.Ident4(), mgl32.Scale3D(-1, 1, 1), mgl32.Scale3D(-1, -1, 1), mgl32.Scale3D(1, -1, 1)}[i%4]
+			case "kaleidoscope":
+				transform = mgl32.HomogRotate3DZ(float32(i) * float32(rotOffset))
+
+				// mirrored copy shares the edge with the previous wedge
+				if i%2 == 1 {
+					transform = mgl32.HomogRotate3DZ(float32(i+1) * float32(rotOffset)).Mul4(mgl32.Scale3D(1, -1, 1))
+				}
+			}
+
+			if len(layout) > 0 {
+				cp := layout[i%len(layout)]
+
+				scale := float32(cp.Scale)
+				if scale <= 0 {
+					scale = 1
+				}
+
+				offset := mgl32.Translate3D(float32(cp.OffsetX)*camera.scale.X32(), float32(cp.OffsetY)*camera.scale.Y32(), 0)
+
+				transform = offset.Mul4(mgl32.Scale3D(scale, scale, 1)).Mul4(transform)
+			}
+
+			camera.cache[i] = camera.projection.Mul4(pos).Mul4(transform).Mul4(view)
 		}
+
 		camera.rebuildCache = false
+		camera.cacheMode = mode
 	}
 
 	return camera.cache
@@ -0,0 +1,15 @@
+package bmath
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// NewRandom returns a generator for one random source derived from the session seed.
+// Each stream gets its own sequence, so extra random calls in one feature don't change the others.
+func NewRandom(seed int64, stream string) *rand.Rand {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(stream))
+
+	return rand.New(rand.NewSource(seed ^ int64(hash.Sum64())))
+}
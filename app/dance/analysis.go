@@ -0,0 +1,289 @@
+package dance
+
+import (
+	"encoding/csv"
+	"github.com/wieku/danser-go/app/beatmap"
+	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/bmath/camera"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Direction changes are checked on movement over this many ms, so tiny jitter doesn't count as a turn
+const turnWindow = 16
+
+// Direction changes sharper than this (in degrees) are counted as sharp turns
+const sharpTurnAngle = 90.0
+
+// DanceMetrics holds movement statistics of a single cursor, collected between the start of the first and the end of the last object.
+type DanceMetrics struct {
+	Cursor int
+
+	// Movers that drove the cursor in order of first use, separated with semicolons
+	Mover string
+
+	// Lengths in osu!pixels, velocity in osu!pixels/ms and acceleration in osu!pixels/ms²
+	PathLength                       float64
+	AvgVelocity, MaxVelocity         float64
+	AvgAcceleration, MaxAcceleration float64
+
+	// Time in ms the cursor spent outside the 512x384 playfield
+	OffPlayfield int64
+
+	SharpTurns int
+
+	// Number of circles and sliders in cursor's queue and start times of those the cursor wasn't inside at hit time
+	Objects       int
+	MissedObjects []int64
+}
+
+type cursorAnalysis struct {
+	metrics *DanceMetrics
+
+	queue     []objects.BaseObject
+	nextCheck int
+
+	lastPos, lastVelocity vector.Vector2f
+	turnPos, lastTurnDir  vector.Vector2f
+
+	velocitySum, accelerationSum float64
+	samples                      int64
+
+	movers    []string
+	lastMover string
+}
+
+// AnalyzeDance runs GenericController over the whole map without rendering and measures movement of every cursor.
+// The beatmap has to have its timing points and objects parsed.
+func AnalyzeDance(bMap *beatmap.BeatMap) ([]*DanceMetrics, error) {
+	controller := NewGenericController().(*GenericController)
+	controller.SetBeatMap(bMap)
+
+	if err := controller.InitCursors(); err != nil {
+		return nil, err
+	}
+
+	sliders := newSliderFollower(bMap)
+
+	startTime, endTime := int64(math.MaxInt64), int64(math.MinInt64)
+
+	for _, o := range bMap.HitObjects {
+		startTime = bmath.MinI64(startTime, o.GetBasicData().StartTime)
+		endTime = bmath.MaxI64(endTime, o.GetBasicData().EndTime)
+	}
+
+	analyses := make([]*cursorAnalysis, len(controller.cursors))
+
+	for i := range analyses {
+		analyses[i] = &cursorAnalysis{
+			metrics: &DanceMetrics{Cursor: i},
+			queue:   controller.queues[i],
+		}
+
+		for _, o := range controller.queues[i] {
+			if _, ok := o.(*objects.Spinner); !ok {
+				analyses[i].metrics.Objects++
+			}
+		}
+	}
+
+	for time := bmath.MinI64(0, startTime); time <= endTime; time++ {
+		sliders.Update(time)
+		controller.Update(time, 1)
+
+		for i, cursor := range controller.cursors {
+			if time == startTime {
+				analyses[i].lastPos = cursor.Position
+				analyses[i].turnPos = cursor.Position
+			}
+
+			if time > startTime {
+				analyses[i].sample(cursor.Position, time-startTime)
+			}
+
+			analyses[i].checkObjects(time, cursor.Position, bMap.Diff.CircleRadius)
+			analyses[i].addMover(controller.schedulers[i].GetMoverName())
+		}
+	}
+
+	result := make([]*DanceMetrics, len(analyses))
+
+	for i, a := range analyses {
+		if a.samples > 0 {
+			a.metrics.AvgVelocity = a.velocitySum / float64(a.samples)
+			a.metrics.AvgAcceleration = a.accelerationSum / float64(a.samples)
+		}
+
+		result[i] = a.metrics
+	}
+
+	return result, nil
+}
+
+// sliderFollower moves sliders along their paths. Sliders update their position only when they are drawn and schedulers follow that position,
+// so it's needed whenever the controller runs without rendering.
+type sliderFollower struct {
+	sliders []*objects.Slider
+	first   int
+}
+
+func newSliderFollower(bMap *beatmap.BeatMap) *sliderFollower {
+	follower := new(sliderFollower)
+
+	for _, o := range bMap.HitObjects {
+		if s, ok := o.(*objects.Slider); ok {
+			follower.sliders = append(follower.sliders, s)
+		}
+	}
+
+	return follower
+}
+
+func (follower *sliderFollower) Update(time int64) {
+	for follower.first < len(follower.sliders) && follower.sliders[follower.first].GetBasicData().EndTime < time {
+		follower.first++
+	}
+
+	for i := follower.first; i < len(follower.sliders) && follower.sliders[i].GetBasicData().StartTime <= time; i++ {
+		if follower.sliders[i].GetBasicData().EndTime >= time {
+			follower.sliders[i].Pos = follower.sliders[i].GetPointAt(time)
+		}
+	}
+}
+
+// addMover records the mover driving the cursor, every mover is listed once in order of first use
+func (analysis *cursorAnalysis) addMover(name string) {
+	if analysis.lastMover == name {
+		return
+	}
+
+	analysis.lastMover = name
+
+	for _, m := range analysis.movers {
+		if m == name {
+			return
+		}
+	}
+
+	analysis.movers = append(analysis.movers, name)
+	analysis.metrics.Mover = strings.Join(analysis.movers, ";")
+}
+
+func (analysis *cursorAnalysis) sample(pos vector.Vector2f, elapsed int64) {
+	metrics := analysis.metrics
+
+	velocity := pos.Sub(analysis.lastPos)
+	acceleration := velocity.Sub(analysis.lastVelocity).Len()
+
+	metrics.PathLength += float64(velocity.Len())
+	metrics.MaxVelocity = math.Max(metrics.MaxVelocity, float64(velocity.Len()))
+
+	analysis.velocitySum += float64(velocity.Len())
+	analysis.samples++
+
+	// first sample doesn't have a previous velocity
+	if analysis.samples > 1 {
+		metrics.MaxAcceleration = math.Max(metrics.MaxAcceleration, float64(acceleration))
+		analysis.accelerationSum += float64(acceleration)
+	}
+
+	if pos.X < 0 || pos.Y < 0 || pos.X > camera.OsuWidth || pos.Y > camera.OsuHeight {
+		metrics.OffPlayfield++
+	}
+
+	if elapsed%turnWindow == 0 {
+		dir := pos.Sub(analysis.turnPos)
+
+		if dir.Len() > 1 {
+			if analysis.lastTurnDir.Len() > 1 {
+				cos := float64(dir.Dot(analysis.lastTurnDir) / (dir.Len() * analysis.lastTurnDir.Len()))
+
+				if math.Acos(bmath.ClampF64(cos, -1, 1)) > sharpTurnAngle*math.Pi/180 {
+					metrics.SharpTurns++
+				}
+			}
+
+			analysis.lastTurnDir = dir
+		}
+
+		analysis.turnPos = pos
+	}
+
+	analysis.lastPos = pos
+	analysis.lastVelocity = velocity
+}
+
+func (analysis *cursorAnalysis) checkObjects(time int64, pos vector.Vector2f, radius float64) {
+	for ; analysis.nextCheck < len(analysis.queue); analysis.nextCheck++ {
+		o := analysis.queue[analysis.nextCheck]
+
+		if o.GetBasicData().StartTime > time {
+			break
+		}
+
+		if _, ok := o.(*objects.Spinner); ok {
+			continue
+		}
+
+		if float64(pos.Dst(o.GetBasicData().StartPos)) > radius {
+			analysis.metrics.MissedObjects = append(analysis.metrics.MissedObjects, o.GetBasicData().StartTime)
+		}
+	}
+}
+
+// WriteDanceMetricsCSV writes one row per cursor, missed objects are written as a list of start times separated by semicolons.
+func WriteDanceMetricsCSV(writer io.Writer, metrics []*DanceMetrics) error {
+	w := csv.NewWriter(writer)
+
+	err := w.Write([]string{"cursor", "mover", "path_length", "avg_velocity", "max_velocity", "avg_acceleration", "max_acceleration", "off_playfield_ms", "sharp_turns", "objects", "missed", "missed_times"})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range metrics {
+		missed := make([]string, len(m.MissedObjects))
+		for i, t := range m.MissedObjects {
+			missed[i] = strconv.FormatInt(t, 10)
+		}
+
+		err = w.Write([]string{
+			strconv.Itoa(m.Cursor),
+			m.Mover,
+			formatMetric(m.PathLength),
+			formatMetric(m.AvgVelocity),
+			formatMetric(m.MaxVelocity),
+			formatMetric(m.AvgAcceleration),
+			formatMetric(m.MaxAcceleration),
+			strconv.FormatInt(m.OffPlayfield, 10),
+			strconv.Itoa(m.SharpTurns),
+			strconv.Itoa(m.Objects),
+			strconv.Itoa(len(m.MissedObjects)),
+			strings.Join(missed, ";"),
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// LogDanceMetrics prints a short summary of every cursor.
+func LogDanceMetrics(metrics []*DanceMetrics) {
+	for _, m := range metrics {
+		log.Printf("Cursor %d (%s): path %.0fpx, velocity avg %.3f max %.3f px/ms, acceleration avg %.4f max %.3f px/ms², off playfield %dms, sharp turns %d, missed %d/%d objects",
+			m.Cursor, m.Mover, m.PathLength, m.AvgVelocity, m.MaxVelocity, m.AvgAcceleration, m.MaxAcceleration, m.OffPlayfield, m.SharpTurns, len(m.MissedObjects), m.Objects)
+	}
+}
+
+func formatMetric(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
@@ -0,0 +1,103 @@
+package dance
+
+import (
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/math/math32"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+)
+
+// avoidCollisions pushes cursors away from each other after schedulers have placed them.
+// Each cursor is moved only as much as it's free: not at all around its own objects and fully halfway between them,
+// so the cursor responsible for the next object is never disturbed.
+func (controller *GenericController) avoidCollisions(time int64) {
+	config := settings.Dance.Avoidance
+
+	distance := float32(config.Distance)
+
+	// offsets can cross the whole push range four times during ReturnTime
+	maxStep := 4 * distance / float32(math.Max(config.ReturnTime, 1)) * float32(bmath.MaxI64(time-controller.avoidTime, 1))
+	controller.avoidTime = time
+
+	base := make([]vector.Vector2f, len(controller.cursors))
+	for i, cursor := range controller.cursors {
+		base[i] = cursor.Position
+	}
+
+	for i, cursor := range controller.cursors {
+		weight := controller.freedom(i, time)
+
+		var push vector.Vector2f
+
+		if weight > 0 {
+			for j := range controller.cursors {
+				if i == j {
+					continue
+				}
+
+				diff := base[i].Sub(base[j])
+
+				dst := diff.Len()
+				if dst >= distance {
+					continue
+				}
+
+				// cursors on the same spot are split in directions given by their index
+				dir := vector.NewVec2fRad(float32(i)*2*math32.Pi/float32(len(controller.cursors)), 1)
+				if dst > 0.001 {
+					dir = diff.Scl(1 / dst)
+				}
+
+				push = push.Add(dir.Scl(distance - dst))
+			}
+		}
+
+		// offsets follow the push with limited speed, so they don't flip when cursors pass through each other,
+		// and are limited by the freedom to reach 0 before the cursor's next object
+		offset := controller.avoidOffsets[i]
+
+		if step := push.Scl(weight).Sub(offset); step.Len() > maxStep {
+			offset = offset.Add(step.Scl(maxStep / step.Len()))
+		} else {
+			offset = offset.Add(step)
+		}
+
+		if maxLen := weight * distance * float32(len(controller.cursors)-1); offset.Len() > maxLen {
+			offset = offset.Scl(maxLen / offset.Len())
+		}
+
+		controller.avoidOffsets[i] = offset
+
+		cursor.Position = base[i].Add(controller.avoidOffsets[i])
+	}
+}
+
+// freedom returns 0 when the cursor is close to or on one of its objects and rises to 1 over Avoidance.ReturnTime between them.
+func (controller *GenericController) freedom(cursor int, time int64) float32 {
+	queue := controller.queues[cursor]
+
+	index := controller.avoidIndices[cursor]
+	for index < len(queue) && queue[index].GetBasicData().EndTime < time {
+		index++
+	}
+
+	controller.avoidIndices[cursor] = index
+
+	// humanized cursors can hit up to the 50 window earlier or later
+	margin := controller.bMap.Diff.Hit50
+
+	gap := math.MaxFloat64
+
+	if index < len(queue) {
+		gap = float64(queue[index].GetBasicData().StartTime - time - margin)
+	}
+
+	if index > 0 {
+		gap = math.Min(gap, float64(time-queue[index-1].GetBasicData().EndTime-margin))
+	}
+
+	returnTime := math.Max(settings.Dance.Avoidance.ReturnTime, 1)
+
+	return float32(bmath.ClampF64(gap/returnTime, 0, 1))
+}
@@ -1,71 +1,147 @@
 package dance
 
 import (
+	"fmt"
 	"github.com/wieku/danser-go/app/beatmap"
+	"github.com/wieku/danser-go/app/beatmap/difficulty"
 	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/bmath"
 	"github.com/wieku/danser-go/app/dance/movers"
 	"github.com/wieku/danser-go/app/dance/schedulers"
 	"github.com/wieku/danser-go/app/dance/spinners"
 	"github.com/wieku/danser-go/app/graphics"
+	"github.com/wieku/danser-go/app/rulesets/osu"
 	"github.com/wieku/danser-go/app/settings"
-	"strings"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"log"
 )
 
 type Controller interface {
 	SetBeatMap(beatMap *beatmap.BeatMap)
-	InitCursors()
+	InitCursors() error
 	Update(time int64, delta float64)
-	GetCursors() []*graphics.Cursor
+	GetCursors() []*graphics.CursorState
 }
 
 type GenericController struct {
 	bMap       *beatmap.BeatMap
-	cursors    []*graphics.Cursor
+	cursors    []*graphics.CursorState
 	schedulers []schedulers.Scheduler
+	queues     [][]objects.BaseObject
+
+	avoidOffsets []vector.Vector2f
+	avoidIndices []int
+	avoidTime    int64
+
+	frameKeys []int
+
+	ruleset  *osu.OsuRuleSet
+	lastTime int64
+	started  bool
+
+	recorders []*replayRecorder
+	endTime   int64
+	exported  bool
 }
 
 func NewGenericController() Controller {
 	return &GenericController{}
 }
 
+// ValidateMovers checks if all movers, timeline entries and tapping styles in Dance settings exist and have valid parameters, so errors are reported before the map starts.
+func ValidateMovers() error {
+	entries := append([]*settings.Mover{}, settings.Dance.Movers...)
+	spinnerEntries := append([]*settings.Mover{}, settings.Dance.Spinners...)
+
+	for _, e := range settings.Dance.Timeline {
+		if e.Mover != nil {
+			entries = append(entries, e.Mover)
+		}
+
+		spinnerEntries = append(spinnerEntries, e.Spinners...)
+	}
+
+	if err := schedulers.ValidateTimeline(settings.Dance.Timeline); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if _, err := movers.Create(e); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range spinnerEntries {
+		if _, err := spinners.Create(e); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range settings.Dance.Tapping {
+		if _, err := schedulers.NewTappingStyle(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (controller *GenericController) SetBeatMap(beatMap *beatmap.BeatMap) {
 	controller.bMap = beatMap
 }
 
-func (controller *GenericController) InitCursors() {
-	controller.cursors = make([]*graphics.Cursor, settings.TAG)
+func (controller *GenericController) InitCursors() error {
+	controller.cursors = make([]*graphics.CursorState, settings.TAG)
 	controller.schedulers = make([]schedulers.Scheduler, settings.TAG)
+	controller.frameKeys = make([]int, settings.TAG)
+
+	humanizer := settings.Dance.Humanizer
+
+	humanizerSeed := humanizer.Seed
+	if humanizerSeed == 0 {
+		humanizerSeed = settings.SEED
+	}
+
+	if humanizer.Enabled {
+		log.Println("Humanizer seed:", humanizerSeed)
+	}
 
 	for i := range controller.cursors {
-		controller.cursors[i] = graphics.NewCursor()
+		controller.cursors[i] = graphics.NewCursorState()
 
-		mover := "flower"
+		moverEntry := settings.NewMover("flower")
 		if len(settings.Dance.Movers) > 0 {
-			mover = strings.ToLower(settings.Dance.Movers[i%len(settings.Dance.Movers)])
+			moverEntry = settings.Dance.Movers[i%len(settings.Dance.Movers)]
 		}
 
-		var scheduler schedulers.Scheduler
+		mover, err := movers.Create(moverEntry)
+		if err != nil {
+			return err
+		}
+
+		if m, ok := mover.(movers.TimingMover); ok {
+			m.SetTimings(controller.bMap.Timings)
+		}
+
+		var timeline *schedulers.Timeline
+		if len(settings.Dance.Timeline) > 0 {
+			timeline, err = schedulers.NewTimeline(controller.bMap, settings.Dance.Timeline)
+			if err != nil {
+				return err
+			}
+		}
 
-		switch mover {
-		case "spline":
-			scheduler = schedulers.NewGenericScheduler(movers.NewSplineMover)
-		case "bezier":
-			scheduler = schedulers.NewGenericScheduler(movers.NewBezierMover)
-		case "circular":
-			scheduler = schedulers.NewGenericScheduler(movers.NewHalfCircleMover)
-		case "linear":
-			scheduler = schedulers.NewGenericScheduler(movers.NewLinearMover)
-		case "axis":
-			scheduler = schedulers.NewGenericScheduler(movers.NewAxisMover)
-		case "aggressive":
-			scheduler = schedulers.NewGenericScheduler(movers.NewAggressiveMover)
-		case "momentum":
-			scheduler = schedulers.NewGenericScheduler(movers.NewMomentumMover)
-		default:
-			scheduler = schedulers.NewGenericScheduler(movers.NewAngleOffsetMover)
+		tapping := settings.NewTapping("default")
+		if len(settings.Dance.Tapping) > 0 {
+			tapping = settings.Dance.Tapping[i%len(settings.Dance.Tapping)]
 		}
 
-		controller.schedulers[i] = scheduler
+		controller.schedulers[i] = schedulers.NewGenericScheduler(mover, moverEntry.GetName(), timeline, tapping, bmath.NewRandom(settings.SEED, fmt.Sprintf("scheduler%d", i)))
+
+		if humanizer.Enabled {
+			random := bmath.NewRandom(humanizerSeed, fmt.Sprintf("humanizer%d", i))
+			controller.schedulers[i] = schedulers.NewHumanizedScheduler(controller.schedulers[i], controller.bMap.Diff, humanizer, random)
+		}
 	}
 
 	type Queue struct {
@@ -109,26 +185,147 @@ func (controller *GenericController) InitCursors() {
 		}
 	}
 
+	if settings.Dance.ExportReplays {
+		controller.recorders = make([]*replayRecorder, len(controller.cursors))
+		for i := range controller.recorders {
+			controller.recorders[i] = newReplayRecorder()
+		}
+
+		for _, o := range controller.bMap.HitObjects {
+			controller.endTime = bmath.MaxI64(controller.endTime, o.GetBasicData().EndTime)
+		}
+	}
+
+	controller.queues = make([][]objects.BaseObject, len(objs))
+	controller.avoidOffsets = make([]vector.Vector2f, len(objs))
+	controller.avoidIndices = make([]int, len(objs))
+
 	for i := range controller.cursors {
-		spinMover := "circle"
+		controller.queues[i] = append([]objects.BaseObject{}, objs[i].objs...)
+
+		spinnerEntry := settings.NewMover("circle")
 		if len(settings.Dance.Spinners) > 0 {
-			spinMover = settings.Dance.Spinners[i%len(settings.Dance.Spinners)]
+			spinnerEntry = settings.Dance.Spinners[i%len(settings.Dance.Spinners)]
+		}
+
+		spinnerMover, err := spinners.Create(spinnerEntry)
+		if err != nil {
+			return err
 		}
 
-		controller.schedulers[i].Init(objs[i].objs, controller.cursors[i], spinners.GetMoverByName(spinMover))
+		if err := controller.schedulers[i].Init(objs[i].objs, controller.cursors[i], spinnerMover); err != nil {
+			return err
+		}
 	}
+
+	return nil
+}
+
+// InitScoring makes the controller score its cursors, so results of humanized plays can be shown. Has to be called after InitCursors.
+func (controller *GenericController) InitScoring() {
+	mods := make([]difficulty.Modifier, len(controller.cursors))
+	for i := range mods {
+		mods[i] = difficulty.None
+	}
+
+	controller.ruleset = osu.NewOsuRuleset(controller.bMap, controller.cursors, mods)
+}
+
+func (controller *GenericController) GetRuleset() *osu.OsuRuleSet {
+	return controller.ruleset
 }
 
 func (controller *GenericController) Update(time int64, delta float64) {
-	for i := range controller.cursors {
+	if controller.ruleset == nil {
+		controller.updateCursors(time)
+	} else {
+		if !controller.started {
+			controller.lastTime = time - 1
+			controller.started = true
+		}
+
+		// Ruleset has to see every millisecond, otherwise short clicks and slider ticks could be skipped
+		for nTime := controller.lastTime + 1; nTime <= time; nTime++ {
+			controller.updateCursors(nTime)
+
+			for _, cursor := range controller.cursors {
+				controller.ruleset.UpdateClickFor(cursor, nTime)
+				controller.ruleset.UpdateNormalFor(cursor, nTime)
+				controller.ruleset.UpdatePostFor(cursor, nTime)
+			}
+
+			controller.ruleset.Update(nTime)
+		}
+
+		controller.lastTime = time
+	}
+
+	if controller.recorders != nil && !controller.exported && time > controller.endTime+1000 {
+		controller.exported = true
+
+		for i, recorder := range controller.recorders {
+			exportReplay(controller.bMap, controller.cursors[i], i, recorder, controller.getStats(controller.cursors[i]))
+		}
+	}
+}
+
+func (controller *GenericController) updateCursors(time int64) {
+	avoid := settings.Dance.Avoidance.Enabled && len(controller.cursors) > 1
+
+	for i, cursor := range controller.cursors {
+		// schedulers continue from their own position, not from the one bent by avoidance
+		if avoid {
+			cursor.Position = cursor.Position.Sub(controller.avoidOffsets[i])
+		}
+
 		controller.schedulers[i].Update(time)
-		controller.cursors[i].Update(delta)
+	}
 
-		controller.cursors[i].LeftButton = controller.cursors[i].LeftKey || controller.cursors[i].LeftMouse
-		controller.cursors[i].RightButton = controller.cursors[i].RightKey || controller.cursors[i].RightMouse
+	if avoid {
+		controller.avoidCollisions(time)
 	}
+
+	for i, cursor := range controller.cursors {
+		cursor.LeftButton = cursor.LeftKey || cursor.LeftMouse
+		cursor.RightButton = cursor.RightKey || cursor.RightMouse
+
+		keys := replayKeys(cursor)
+
+		// Replay frames are used by the ruleset and exported replays, so both see the same frames
+		if keys != controller.frameKeys[i] || time-cursor.CurrentFrameTime >= replayFrameInterval {
+			cursor.LastFrameTime = cursor.CurrentFrameTime
+			cursor.CurrentFrameTime = time
+			cursor.IsReplayFrame = true
+
+			controller.frameKeys[i] = keys
+
+			if controller.recorders != nil {
+				controller.recorders[i].record(time, cursor)
+			}
+		} else {
+			cursor.IsReplayFrame = false
+		}
+	}
+}
+
+func (controller *GenericController) getStats(cursor *graphics.CursorState) (stats replayStats) {
+	if controller.ruleset == nil {
+		return
+	}
+
+	_, combo, score, _ := controller.ruleset.GetResults(cursor)
+
+	stats.count300 = uint16(controller.ruleset.GetHitCount(cursor, osu.Hit300))
+	stats.count100 = uint16(controller.ruleset.GetHitCount(cursor, osu.Hit100))
+	stats.count50 = uint16(controller.ruleset.GetHitCount(cursor, osu.Hit50))
+	stats.countMiss = uint16(controller.ruleset.GetHitCount(cursor, osu.Miss))
+	stats.score = int32(score)
+	stats.maxCombo = uint16(combo)
+	stats.perfect = stats.countMiss == 0 // slider breaks aren't tracked by the ruleset
+
+	return
 }
 
-func (controller *GenericController) GetCursors() []*graphics.Cursor {
+func (controller *GenericController) GetCursors() []*graphics.CursorState {
 	return controller.cursors
 }
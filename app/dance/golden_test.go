@@ -0,0 +1,86 @@
+package dance
+
+import (
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/app/testutil"
+	"strings"
+	"testing"
+)
+
+// Cursor positions and keys are recorded every 25ms, movers are checked through GenericController so schedulers and input are covered too.
+// Sliders are moved like during drawing and the cursor has to stay on them while they are active.
+func TestGoldenMovers(t *testing.T) {
+	oldMovers, oldTag := settings.Dance.Movers, settings.TAG
+
+	defer func() {
+		settings.Dance.Movers, settings.TAG = oldMovers, oldTag
+	}()
+
+	for _, name := range []string{"spline", "momentum", "flower", "spring", "lissajous"} {
+		t.Run(name, func(t *testing.T) {
+			bMap := testutil.LoadBeatMap(t, "golden", "golden.osu")
+
+			settings.Dance.Movers = []*settings.Mover{settings.NewMover(name)}
+			settings.TAG = 1
+
+			controller := NewGenericController()
+			controller.SetBeatMap(bMap)
+			if err := controller.InitCursors(); err != nil {
+				t.Fatal(err)
+			}
+
+			cursor := controller.GetCursors()[0]
+			sliders := newSliderFollower(bMap)
+
+			golden := &testutil.Golden{}
+
+			for time := int64(500); time <= 15500; time++ {
+				sliders.Update(time)
+				controller.Update(time, 1)
+
+				for _, s := range sliders.sliders {
+					if time >= s.GetBasicData().StartTime && time <= s.GetBasicData().EndTime && cursor.Position.Dst(s.Pos) > 0.01 {
+						t.Fatalf("cursor at %v doesn't follow the slider at %v, time %d", cursor.Position, s.Pos, time)
+					}
+				}
+
+				if time%25 == 0 {
+					golden.Printf("%d %.3f,%.3f keys=%d", time, cursor.Position.X, cursor.Position.Y, replayKeys(cursor))
+				}
+			}
+
+			golden.Check(t, "mover-"+name)
+		})
+	}
+}
+
+// Metrics of a few movers, so changes in their movement show up even when positions are hard to review.
+// A timeline entry switches movers in the middle of the map, so it's reported which movers drove each cursor.
+func TestGoldenAnalysis(t *testing.T) {
+	oldMovers, oldTimeline, oldTag := settings.Dance.Movers, settings.Dance.Timeline, settings.TAG
+
+	defer func() {
+		settings.Dance.Movers, settings.Dance.Timeline, settings.TAG = oldMovers, oldTimeline, oldTag
+	}()
+
+	settings.Dance.Movers = []*settings.Mover{settings.NewMover("spline"), settings.NewMover("momentum"), settings.NewMover("linear")}
+	settings.Dance.Timeline = []*settings.TimelineEntry{{Type: "time", StartTime: 9000, EndTime: 12000, Mover: settings.NewMover("flower")}}
+	settings.TAG = 3
+
+	bMap := testutil.LoadBeatMap(t, "golden", "golden.osu")
+
+	metrics, err := AnalyzeDance(bMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var builder strings.Builder
+
+	if err := WriteDanceMetricsCSV(&builder, metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := &testutil.Golden{}
+	golden.Printf("%s", strings.TrimSuffix(builder.String(), "\n"))
+	golden.Check(t, "analysis")
+}
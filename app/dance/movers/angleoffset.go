@@ -16,10 +16,11 @@ type AngleOffsetMover struct {
 	bz                 *curves.Bezier
 	startTime, endTime int64
 	invert             float32
+	config             *settings.Flower
 }
 
-func NewAngleOffsetMover() MultiPointMover {
-	return &AngleOffsetMover{lastAngle: 0, invert: 1}
+func NewAngleOffsetMover(config *settings.Flower) MultiPointMover {
+	return &AngleOffsetMover{lastAngle: 0, invert: 1, config: config}
 }
 
 func (bm *AngleOffsetMover) Reset() {
@@ -44,16 +45,16 @@ func (bm *AngleOffsetMover) SetObjects(objs []objects.BaseObject) int {
 
 	var points []vector.Vector2f
 
-	scaledDistance := distance * float32(settings.Dance.Flower.DistanceMult)
-	newAngle := float32(settings.Dance.Flower.AngleOffset) * math32.Pi / 180.0
+	scaledDistance := distance * float32(bm.config.DistanceMult)
+	newAngle := float32(bm.config.AngleOffset) * math32.Pi / 180.0
 
-	if end.GetBasicData().StartTime > 0 && settings.Dance.Flower.LongJump >= 0 && (startTime-endTime) > settings.Dance.Flower.LongJump {
-		scaledDistance = float32(startTime-endTime) * float32(settings.Dance.Flower.LongJumpMult)
+	if end.GetBasicData().StartTime > 0 && bm.config.LongJump >= 0 && (startTime-endTime) > bm.config.LongJump {
+		scaledDistance = float32(startTime-endTime) * float32(bm.config.LongJumpMult)
 	}
 
 	if endPos == startPos {
-		if settings.Dance.Flower.LongJumpOnEqualPos {
-			scaledDistance = float32(startTime-endTime) * float32(settings.Dance.Flower.LongJumpMult)
+		if bm.config.LongJumpOnEqualPos {
+			scaledDistance = float32(startTime-endTime) * float32(bm.config.LongJumpMult)
 
 			if math.Abs(float64(startTime-endTime)) > 1 {
 				bm.lastAngle += math.Pi
@@ -110,9 +111,9 @@ func (bm *AngleOffsetMover) SetObjects(objs []objects.BaseObject) int {
 
 		points = []vector.Vector2f{endPos, pt1, pt2, startPos}
 	} else {
-		if math.Abs(float64(startTime-endTime)) > 1 && bmath.AngleBetween32(endPos, bm.lastPoint, startPos) >= float32(settings.Dance.Flower.AngleOffset)*math32.Pi/180.0 {
+		if math.Abs(float64(startTime-endTime)) > 1 && bmath.AngleBetween32(endPos, bm.lastPoint, startPos) >= float32(bm.config.AngleOffset)*math32.Pi/180.0 {
 			bm.invert = -1 * bm.invert
-			newAngle = float32(settings.Dance.Flower.StreamAngleOffset) * math32.Pi / 180.0
+			newAngle = float32(bm.config.StreamAngleOffset) * math32.Pi / 180.0
 		}
 
 		angle := endPos.AngleRV(startPos) - newAngle*bm.invert
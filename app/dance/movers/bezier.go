@@ -15,10 +15,11 @@ type BezierMover struct {
 	beginTime, endTime int64
 	previousSpeed      float32
 	invert             float32
+	config             *settings.Bezier
 }
 
-func NewBezierMover() MultiPointMover {
-	bm := &BezierMover{invert: 1}
+func NewBezierMover(config *settings.Bezier) MultiPointMover {
+	bm := &BezierMover{invert: 1, config: config}
 	bm.pt = vector.NewVec2f(512/2, 384/2)
 	bm.previousSpeed = -1
 	return bm
@@ -51,8 +52,8 @@ func (bm *BezierMover) SetObjects(objs []objects.BaseObject) int {
 
 	genScale := bm.previousSpeed
 
-	aggressiveness := float32(settings.Dance.Bezier.Aggressiveness)
-	sliderAggressiveness := float32(settings.Dance.Bezier.SliderAggressiveness)
+	aggressiveness := float32(bm.config.Aggressiveness)
+	sliderAggressiveness := float32(bm.config.SliderAggressiveness)
 
 	if endPos == startPos {
 		points = []vector.Vector2f{endPos, startPos}
@@ -13,10 +13,11 @@ type HalfCircleMover struct {
 	ca                 curves.Curve
 	startTime, endTime int64
 	invert             float32
+	config             *settings.Circular
 }
 
-func NewHalfCircleMover() MultiPointMover {
-	return &HalfCircleMover{invert: -1}
+func NewHalfCircleMover(config *settings.Circular) MultiPointMover {
+	return &HalfCircleMover{invert: -1, config: config}
 }
 
 func (bm *HalfCircleMover) Reset() {
@@ -32,7 +33,7 @@ func (bm *HalfCircleMover) SetObjects(objs []objects.BaseObject) int {
 	bm.endTime = end.GetBasicData().EndTime
 	bm.startTime = start.GetBasicData().StartTime
 
-	if settings.Dance.HalfCircle.StreamTrigger < 0 || (bm.startTime-bm.endTime) < settings.Dance.HalfCircle.StreamTrigger {
+	if bm.config.StreamTrigger < 0 || (bm.startTime-bm.endTime) < bm.config.StreamTrigger {
 		bm.invert = -1 * bm.invert
 	}
 
@@ -42,7 +43,7 @@ func (bm *HalfCircleMover) SetObjects(objs []objects.BaseObject) int {
 	}
 
 	point := endPos.Mid(startPos)
-	p := point.Sub(endPos).Rotate(bm.invert * math.Pi / 2).Scl(float32(settings.Dance.HalfCircle.RadiusMultiplier)).Add(point)
+	p := point.Sub(endPos).Rotate(bm.invert * math.Pi / 2).Scl(float32(bm.config.RadiusMultiplier)).Add(point)
 	bm.ca = curves.NewCirArc(endPos, p, startPos)
 
 	return 2
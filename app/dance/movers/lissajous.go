@@ -0,0 +1,70 @@
+package movers
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/math/animation/easing"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+)
+
+// LissajousMover traces a Lissajous figure around the path between objects in gaps longer than MinGap.
+// The figure fades in and out with the movement, so the cursor still starts and ends on the objects. Shorter gaps are eased lines.
+type LissajousMover struct {
+	config *settings.Lissajous
+
+	from, to           vector.Vector2d
+	endTime, startTime int64
+}
+
+func NewLissajousMover(config *settings.Lissajous) (MultiPointMover, error) {
+	if config.Radius < 0 {
+		return nil, fmt.Errorf("Radius can't be negative")
+	}
+
+	if config.FrequencyX <= 0 || config.FrequencyY <= 0 {
+		return nil, fmt.Errorf("FrequencyX and FrequencyY have to be above 0")
+	}
+
+	return &LissajousMover{config: config}, nil
+}
+
+func (mover *LissajousMover) Reset() {}
+
+func (mover *LissajousMover) SetObjects(objs []objects.BaseObject) int {
+	end, start := objs[0].GetBasicData(), objs[1].GetBasicData()
+
+	mover.from, mover.to = end.EndPos.Copy64(), start.StartPos.Copy64()
+	mover.endTime, mover.startTime = end.EndTime, start.StartTime
+
+	return 2
+}
+
+func (mover *LissajousMover) Update(time int64) vector.Vector2f {
+	duration := mover.startTime - mover.endTime
+	if duration <= 0 {
+		return mover.to.Copy32()
+	}
+
+	t := bmath.ClampF64(float64(time-mover.endTime)/float64(duration), 0, 1)
+
+	position := mover.from.Add(mover.to.Sub(mover.from).Scl(easing.InOutSine(t)))
+
+	if duration < mover.config.MinGap {
+		return position.Copy32()
+	}
+
+	angle := 2 * math.Pi * mover.config.Speed * float64(time-mover.endTime) / 1000
+	envelope := mover.config.Radius * math.Sin(math.Pi*t)
+
+	x := math.Sin(mover.config.FrequencyX*angle + mover.config.Phase*math.Pi/180)
+	y := math.Sin(mover.config.FrequencyY * angle)
+
+	return position.AddS(x*envelope, y*envelope).Copy32()
+}
+
+func (mover *LissajousMover) GetEndTime() int64 {
+	return mover.startTime
+}
@@ -18,10 +18,11 @@ type MomentumMover struct {
 	startTime int64
 	endTime   int64
 	first     bool
+	config    *settings.Momentum
 }
 
-func NewMomentumMover() MultiPointMover {
-	return &MomentumMover{last: vector.NewVec2f(0, 0), first: true}
+func NewMomentumMover(config *settings.Momentum) MultiPointMover {
+	return &MomentumMover{last: vector.NewVec2f(0, 0), first: true, config: config}
 }
 
 func (bm *MomentumMover) Reset() {
@@ -29,15 +30,17 @@ func (bm *MomentumMover) Reset() {
 	bm.last = vector.NewVec2f(0, 0)
 }
 
-func same(o1 objects.BaseObject, o2 objects.BaseObject) bool {
+func (bm *MomentumMover) same(o1 objects.BaseObject, o2 objects.BaseObject) bool {
 	d1 := o1.GetBasicData()
 	d2 := o2.GetBasicData()
-	return d1.StartPos == d2.StartPos || (settings.Dance.Momentum.SkipStackAngles && d1.StartPos.Sub(d2.StackOffset) == d2.StartPos.Sub(d2.StackOffset))
+	return d1.StartPos == d2.StartPos || (bm.config.SkipStackAngles && d1.StartPos.Sub(d2.StackOffset) == d2.StartPos.Sub(d2.StackOffset))
 }
 
 func (bm *MomentumMover) SetObjects(objs []objects.BaseObject) int {
 	i := 0
-	if bm.first { i = 1 }
+	if bm.first && len(objs) > 2 {
+		i = 1
+	}
 
 	end := objs[i+0]
 	start := objs[i+1]
@@ -56,11 +59,11 @@ func (bm *MomentumMover) SetObjects(objs []objects.BaseObject) int {
 			fromSlider = true
 			break
 		}
-		if i == len(objs) - 1 {
+		if i == len(objs)-1 {
 			a2 = bm.last.AngleRV(endPos)
 			break
 		}
-		if !same(o, objs[i+1]) {
+		if !bm.same(o, objs[i+1]) {
 			a2 = o.GetBasicData().StartPos.AngleRV(objs[i+1].GetBasicData().StartPos)
 			break
 		}
@@ -76,19 +79,19 @@ func (bm *MomentumMover) SetObjects(objs []objects.BaseObject) int {
 	}
 
 	a := startPos.AngleRV(endPos)
-	offset := float32(settings.Dance.Momentum.RestrictAngle * math.Pi / 180.0)
-	if !fromSlider && math32.Abs(a2 - a) < offset {
-		if a2 - a < offset {
+	offset := float32(bm.config.RestrictAngle * math.Pi / 180.0)
+	if !fromSlider && math32.Abs(a2-a) < offset {
+		if a2-a < offset {
 			a2 = a - offset
 		} else {
 			a2 = a + offset
 		}
 	}
 
-	p1 := vector.NewVec2fRad(a1, dst * float32(settings.Dance.Momentum.DistanceMult)).Add(endPos)
-	p2 := vector.NewVec2fRad(a2, dst * float32(settings.Dance.Momentum.DistanceMultEnd)).Add(startPos)
+	p1 := vector.NewVec2fRad(a1, dst*float32(bm.config.DistanceMult)).Add(endPos)
+	p2 := vector.NewVec2fRad(a2, dst*float32(bm.config.DistanceMultEnd)).Add(startPos)
 
-	if !same(end, start) {
+	if !bm.same(end, start) {
 		bm.last = p2
 	}
 
@@ -11,3 +11,8 @@ type MultiPointMover interface {
 	Update(time int64) vector.Vector2f
 	GetEndTime() int64
 }
+
+// TimingMover is implemented by movers that need beatmap's timing points, like the scripted mover exposing beat progress.
+type TimingMover interface {
+	SetTimings(timings *objects.Timings)
+}
@@ -0,0 +1,184 @@
+package movers
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/settings"
+	"sort"
+	"strings"
+)
+
+type Registration struct {
+	Name string
+
+	// NewConfig returns a fresh parameter block filled with default values, it's nil if the mover has no parameters
+	NewConfig func() interface{}
+
+	// Create returns a new mover using the parameter block from NewConfig, or an error if the parameters are invalid
+	Create func(config interface{}) (MultiPointMover, error)
+}
+
+var registry = make(map[string]*Registration)
+
+func init() {
+	Register(&Registration{
+		Name: "flower",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.Flower
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewAngleOffsetMover(config.(*settings.Flower)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "spline",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.Spline
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewSplineMover(config.(*settings.Spline)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "bezier",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.Bezier
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewBezierMover(config.(*settings.Bezier)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "circular",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.HalfCircle
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewHalfCircleMover(config.(*settings.Circular)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "momentum",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.Momentum
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewMomentumMover(config.(*settings.Momentum)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "spring",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.Spring
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewSpringMover(config.(*settings.Spring))
+		},
+	})
+
+	Register(&Registration{
+		Name: "lissajous",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.Lissajous
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewLissajousMover(config.(*settings.Lissajous))
+		},
+	})
+
+	Register(&Registration{
+		Name: "scripted",
+		NewConfig: func() interface{} {
+			config := *settings.Dance.Scripted
+			config.Variables = append([]string{}, config.Variables...)
+			return &config
+		},
+		Create: func(config interface{}) (MultiPointMover, error) {
+			return NewScriptedMover(config.(*settings.Scripted))
+		},
+	})
+
+	Register(&Registration{
+		Name: "linear",
+		Create: func(interface{}) (MultiPointMover, error) {
+			return NewLinearMover(), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "axis",
+		Create: func(interface{}) (MultiPointMover, error) {
+			return NewAxisMover(), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "aggressive",
+		Create: func(interface{}) (MultiPointMover, error) {
+			return NewAggressiveMover(), nil
+		},
+	})
+}
+
+// Register makes a mover available under the given name in Dance.Movers.
+func Register(registration *Registration) {
+	name := strings.ToLower(registration.Name)
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("mover \"%s\" is already registered", name))
+	}
+
+	registry[name] = registration
+}
+
+// Names returns sorted names of all registered movers.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Create builds a mover described by a settings entry, its parameter block is applied over mover's defaults.
+func Create(entry *settings.Mover) (MultiPointMover, error) {
+	registration, ok := registry[entry.GetName()]
+	if !ok {
+		return nil, fmt.Errorf("unknown mover \"%s\", available movers: %s", entry.Name, strings.Join(Names(), ", "))
+	}
+
+	var config interface{}
+	if registration.NewConfig != nil {
+		config = registration.NewConfig()
+	}
+
+	if len(entry.Config) > 0 && config == nil {
+		return nil, fmt.Errorf("mover \"%s\" doesn't have any parameters", entry.Name)
+	}
+
+	if err := entry.DecodeConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid parameters for mover \"%s\": %w", entry.Name, err)
+	}
+
+	mover, err := registration.Create(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for mover \"%s\": %w", entry.Name, err)
+	}
+
+	return mover, nil
+}
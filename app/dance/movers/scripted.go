@@ -0,0 +1,211 @@
+package movers
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/math/expression"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+	"regexp"
+)
+
+var scriptVariableRegex = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=([^=].*)$`)
+
+type scriptVariable struct {
+	slot int
+	expr *expression.Expression
+}
+
+// ScriptedMover moves the cursor along a path given by user expressions.
+// Variables available to expressions:
+//
+//	t - movement progress (0-1), time - current time in ms,
+//	startPos, endPos - positions the movement starts and ends at, startTime, endTime, duration - movement times in ms,
+//	distance, angle - distance and direction from startPos to endPos,
+//	startAngle - direction the cursor leaves startPos (slider's end or previous movement), endAngle - direction the cursor should arrive at endPos (slider's start or next movement),
+//	index - movement number, beat - progress of the current beat (0-1), beatLength - beat length in ms, kiai - 1 in kiai sections, 0 otherwise.
+//
+// All angles are in radians.
+type ScriptedMover struct {
+	config  *settings.Scripted
+	timings *objects.Timings
+
+	env       *expression.Environment
+	variables []scriptVariable
+	position  *expression.Expression
+
+	t, time, startPos, endPos, startTime, endTime, duration int
+	distance, angle, startAngle, endAngle, index            int
+	beat, beatLength, kiai                                  int
+
+	from, to          vector.Vector2f
+	beginTime, finish int64
+	lastAngle         float64
+	count             int
+}
+
+// NewScriptedMover compiles expressions from config, returning an error pointing at the first invalid one.
+func NewScriptedMover(config *settings.Scripted) (MultiPointMover, error) {
+	mover := &ScriptedMover{config: config, env: expression.NewEnvironment(), lastAngle: math.NaN()}
+
+	declared := make(map[string]bool)
+
+	declare := func(slot *int, name string, t expression.Type) {
+		*slot = mover.env.Declare(name, t)
+		declared[name] = true
+	}
+
+	declare(&mover.t, "t", expression.Scalar)
+	declare(&mover.time, "time", expression.Scalar)
+	declare(&mover.startPos, "startPos", expression.Vector)
+	declare(&mover.endPos, "endPos", expression.Vector)
+	declare(&mover.startTime, "startTime", expression.Scalar)
+	declare(&mover.endTime, "endTime", expression.Scalar)
+	declare(&mover.duration, "duration", expression.Scalar)
+	declare(&mover.distance, "distance", expression.Scalar)
+	declare(&mover.angle, "angle", expression.Scalar)
+	declare(&mover.startAngle, "startAngle", expression.Scalar)
+	declare(&mover.endAngle, "endAngle", expression.Scalar)
+	declare(&mover.index, "index", expression.Scalar)
+	declare(&mover.beat, "beat", expression.Scalar)
+	declare(&mover.beatLength, "beatLength", expression.Scalar)
+	declare(&mover.kiai, "kiai", expression.Scalar)
+
+	for i, line := range config.Variables {
+		match := scriptVariableRegex.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("Variables[%d]: \"%s\" is not in \"name = expression\" format", i, line)
+		}
+
+		name := match[1]
+
+		if declared[name] {
+			return nil, fmt.Errorf("Variables[%d]: variable \"%s\" is already defined", i, name)
+		}
+
+		expr, err := expression.Compile(match[2], mover.env)
+		if err != nil {
+			return nil, fmt.Errorf("Variables[%d] (%s): %w", i, name, err)
+		}
+
+		slot := 0
+		declare(&slot, name, expr.Type())
+
+		mover.variables = append(mover.variables, scriptVariable{slot, expr})
+	}
+
+	position, err := expression.Compile(config.Position, mover.env)
+	if err != nil {
+		return nil, fmt.Errorf("Position: %w", err)
+	}
+
+	if position.Type() != expression.Vector {
+		return nil, fmt.Errorf("Position: expression has to return a vector, got a %s", position.Type())
+	}
+
+	mover.position = position
+
+	return mover, nil
+}
+
+func (mover *ScriptedMover) SetTimings(timings *objects.Timings) {
+	mover.timings = timings
+}
+
+func (mover *ScriptedMover) Reset() {
+	mover.count = 0
+	mover.lastAngle = math.NaN()
+}
+
+func (mover *ScriptedMover) SetObjects(objs []objects.BaseObject) int {
+	end, start := objs[0], objs[1]
+
+	mover.from = end.GetBasicData().EndPos
+	mover.to = start.GetBasicData().StartPos
+	mover.beginTime = end.GetBasicData().EndTime
+	mover.finish = start.GetBasicData().StartTime
+
+	angle := float64(mover.to.AngleRV(mover.from))
+	if mover.from == mover.to && !math.IsNaN(mover.lastAngle) {
+		angle = mover.lastAngle
+	}
+
+	startAngle := mover.lastAngle
+	if s, ok := end.(*objects.Slider); ok {
+		startAngle = float64(s.GetEndAngle())
+	} else if math.IsNaN(startAngle) {
+		startAngle = angle
+	}
+
+	endAngle := angle
+	if s, ok := start.(*objects.Slider); ok {
+		endAngle = float64(s.GetStartAngle()) + math.Pi
+	} else if len(objs) > 2 && objs[2].GetBasicData().StartPos != mover.to {
+		endAngle = float64(objs[2].GetBasicData().StartPos.AngleRV(mover.to))
+	}
+
+	mover.env.SetVector(mover.startPos, float64(mover.from.X), float64(mover.from.Y))
+	mover.env.SetVector(mover.endPos, float64(mover.to.X), float64(mover.to.Y))
+	mover.env.SetScalar(mover.startTime, float64(mover.beginTime))
+	mover.env.SetScalar(mover.endTime, float64(mover.finish))
+	mover.env.SetScalar(mover.duration, float64(mover.finish-mover.beginTime))
+	mover.env.SetScalar(mover.distance, float64(mover.from.Dst(mover.to)))
+	mover.env.SetScalar(mover.angle, angle)
+	mover.env.SetScalar(mover.startAngle, startAngle)
+	mover.env.SetScalar(mover.endAngle, endAngle)
+	mover.env.SetScalar(mover.index, float64(mover.count))
+
+	mover.lastAngle = angle
+	mover.count++
+
+	return 2
+}
+
+func (mover *ScriptedMover) Update(time int64) vector.Vector2f {
+	t := bmath.ClampF64(float64(time-mover.beginTime)/float64(mover.finish-mover.beginTime), 0, 1)
+	if mover.finish == mover.beginTime {
+		t = 1
+	}
+
+	mover.env.SetScalar(mover.t, t)
+	mover.env.SetScalar(mover.time, float64(time))
+
+	beat, beatLength, kiai := 0.0, 0.0, 0.0
+
+	if mover.timings != nil && len(mover.timings.Points) > 0 {
+		point := mover.timings.GetPoint(time)
+
+		if point.BaseBpm > 0 {
+			beatLength = point.BaseBpm
+			beat = float64(time-point.Time) / beatLength
+			beat -= math.Floor(beat)
+		}
+
+		if point.Kiai {
+			kiai = 1
+		}
+	}
+
+	mover.env.SetScalar(mover.beat, beat)
+	mover.env.SetScalar(mover.beatLength, beatLength)
+	mover.env.SetScalar(mover.kiai, kiai)
+
+	for _, v := range mover.variables {
+		mover.env.Set(v.slot, v.expr.Evaluate())
+	}
+
+	pos := mover.position.Evaluate()
+
+	// Keep the cursor on screen if the script divides by zero or similar
+	if math.IsNaN(pos.X) || math.IsNaN(pos.Y) || math.IsInf(pos.X, 0) || math.IsInf(pos.Y, 0) {
+		return mover.from.Add(mover.to.Sub(mover.from).Scl(float32(t)))
+	}
+
+	return vector.NewVec2f(float32(pos.X), float32(pos.Y))
+}
+
+func (mover *ScriptedMover) GetEndTime() int64 {
+	return mover.finish
+}
@@ -12,10 +12,11 @@ import (
 type SplineMover struct {
 	curve              *curves.BSpline
 	startTime, endTime int64
+	config             *settings.Spline
 }
 
-func NewSplineMover() MultiPointMover {
-	return &SplineMover{}
+func NewSplineMover(config *settings.Spline) MultiPointMover {
+	return &SplineMover{config: config}
 }
 
 func (mover *SplineMover) Reset() {
@@ -90,7 +91,7 @@ func (mover *SplineMover) SetObjects(objs []objects.BaseObject) int {
 				sq1 := pos1.DstSq(pos2)
 				sq2 := pos2.DstSq(pos3)
 
-				if sq1 > max && sq2 > max && settings.Dance.Spline.RotationalForce {
+				if sq1 > max && sq2 > max && mover.config.RotationalForce {
 					if stream {
 						angle = 0
 						stream = false
@@ -103,7 +104,7 @@ func (mover *SplineMover) SetObjects(objs []objects.BaseObject) int {
 							angle = float32(ang) * 90 / 180 * math32.Pi
 						}
 					}
-				} else if sq1 >= min && sq2 >= min && sq1 <= max && sq2 <= max && (settings.Dance.Spline.StreamWobble || settings.Dance.Spline.StreamHalfCircle) {
+				} else if sq1 >= min && sq2 >= min && sq1 <= max && sq2 <= max && (mover.config.StreamWobble || mover.config.StreamHalfCircle) {
 					if stream {
 						angle *= -1
 
@@ -132,11 +133,11 @@ func (mover *SplineMover) SetObjects(objs []objects.BaseObject) int {
 					mid := pos1.Mid(pos2)
 
 					scale := float32(1.0)
-					if stream && !settings.Dance.Spline.StreamHalfCircle {
-						scale = float32(settings.Dance.Spline.WobbleScale)
+					if stream && !mover.config.StreamHalfCircle {
+						scale = float32(mover.config.WobbleScale)
 					}
 
-					if stream && settings.Dance.Spline.StreamHalfCircle {
+					if stream && mover.config.StreamHalfCircle {
 						sign := -1
 						if angle < 0 {
 							sign = 1
@@ -0,0 +1,120 @@
+package movers
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+)
+
+// Spring's angular frequency times movement duration is kept below this. An undamped spring can't reach the object
+// at pi whatever the impulse, and stiff damped springs would need huge impulses on long gaps
+const springMaxPhase = 0.9 * math.Pi
+
+// SpringMover simulates the cursor as a mass on a damped spring. The spring is anchored at the next object,
+// pulled towards the one after it by Lookahead. When the cursor leaves an object it gets an impulse solved
+// so that it lands exactly on the next object at its StartTime.
+type SpringMover struct {
+	config *settings.Spring
+
+	omega, zeta float64
+
+	anchor, displacement, velocity vector.Vector2d
+	duration                       float64
+
+	endPos             vector.Vector2f
+	endTime, startTime int64
+}
+
+func NewSpringMover(config *settings.Spring) (MultiPointMover, error) {
+	if config.Frequency <= 0 {
+		return nil, fmt.Errorf("Frequency has to be above 0")
+	}
+
+	if config.Damping < 0 {
+		return nil, fmt.Errorf("Damping can't be negative")
+	}
+
+	return &SpringMover{config: config}, nil
+}
+
+func (mover *SpringMover) Reset() {}
+
+func (mover *SpringMover) SetObjects(objs []objects.BaseObject) int {
+	end, start := objs[0].GetBasicData(), objs[1].GetBasicData()
+
+	mover.endTime, mover.startTime = end.EndTime, start.StartTime
+	mover.endPos = start.StartPos
+
+	mover.duration = math.Max(float64(mover.startTime-mover.endTime), 1) / 1000
+
+	target := start.StartPos.Copy64()
+
+	mover.anchor = target
+
+	if len(objs) > 2 && mover.config.Lookahead > 0 {
+		next := objs[2].GetBasicData().StartPos.Copy64()
+		mover.anchor = target.Add(next.Sub(target).Scl(mover.config.Lookahead))
+	}
+
+	mover.omega = 2 * math.Pi * mover.config.Frequency
+	mover.zeta = mover.config.Damping
+
+	if phase := mover.omega * mover.duration; phase > springMaxPhase {
+		mover.omega = springMaxPhase / mover.duration
+	}
+
+	mover.displacement = end.EndPos.Copy64().Sub(mover.anchor)
+
+	// Impulse solving displacement(duration) = target - anchor
+	a, b := mover.response(mover.duration)
+	mover.velocity = target.Sub(mover.anchor).Sub(mover.displacement.Scl(a)).Scl(1 / b)
+
+	return 2
+}
+
+// response returns A(t) and B(t) of the oscillator, displacement(t) = A(t)*displacement(0) + B(t)*velocity(0).
+func (mover *SpringMover) response(t float64) (a, b float64) {
+	w, z := mover.omega, mover.zeta
+
+	switch {
+	case z < 1:
+		wd := w * math.Sqrt(1-z*z)
+		decay := math.Exp(-z * w * t)
+
+		a = decay * (math.Cos(wd*t) + z*w/wd*math.Sin(wd*t))
+		b = decay * math.Sin(wd*t) / wd
+	case z == 1:
+		decay := math.Exp(-w * t)
+
+		a = decay * (1 + w*t)
+		b = decay * t
+	default:
+		root := math.Sqrt(z*z - 1)
+		r1, r2 := -w*(z-root), -w*(z+root)
+		e1, e2 := math.Exp(r1*t), math.Exp(r2*t)
+
+		a = (r2*e1 - r1*e2) / (r2 - r1)
+		b = (e2 - e1) / (r2 - r1)
+	}
+
+	return
+}
+
+func (mover *SpringMover) Update(time int64) vector.Vector2f {
+	if time >= mover.startTime {
+		return mover.endPos
+	}
+
+	t := bmath.ClampF64(float64(time-mover.endTime)/1000, 0, mover.duration)
+
+	a, b := mover.response(t)
+
+	return mover.anchor.Add(mover.displacement.Scl(a)).Add(mover.velocity.Scl(b)).Copy32()
+}
+
+func (mover *SpringMover) GetEndTime() int64 {
+	return mover.startTime
+}
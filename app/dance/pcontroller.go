@@ -12,7 +12,7 @@ import (
 
 type PlayerController struct {
 	bMap     *beatmap.BeatMap
-	cursors  []*graphics.Cursor
+	cursors  []*graphics.CursorState
 	window   *glfw.Window
 	ruleset  *osu.OsuRuleSet
 	lastTime int64
@@ -30,8 +30,8 @@ func (controller *PlayerController) SetBeatMap(beatMap *beatmap.BeatMap) {
 	controller.bMap = beatMap
 }
 
-func (controller *PlayerController) InitCursors() {
-	controller.cursors = []*graphics.Cursor{graphics.NewCursor()}
+func (controller *PlayerController) InitCursors() error {
+	controller.cursors = []*graphics.CursorState{graphics.NewCursorState()}
 	controller.cursors[0].IsPlayer = true
 	controller.window = glfw.GetCurrentContext()
 	controller.ruleset = osu.NewOsuRuleset(controller.bMap, controller.cursors, []difficulty.Modifier{difficulty.None})
@@ -53,6 +53,8 @@ func (controller *PlayerController) InitCursors() {
 			}
 		}
 	})
+
+	return nil
 }
 
 func (controller *PlayerController) Update(time int64, delta float64) {
@@ -86,14 +88,12 @@ func (controller *PlayerController) Update(time int64, delta float64) {
 	controller.ruleset.Update(time)
 
 	controller.lastTime = time
-
-	controller.cursors[0].Update(delta)
 }
 
 func (controller *PlayerController) GetRuleset() *osu.OsuRuleSet {
 	return controller.ruleset
 }
 
-func (controller *PlayerController) GetCursors() []*graphics.Cursor {
+func (controller *PlayerController) GetCursors() []*graphics.CursorState {
 	return controller.cursors
 }
@@ -56,7 +56,7 @@ func NewSubControl() *subControl {
 type ReplayController struct {
 	bMap        *beatmap.BeatMap
 	replays     []RpData
-	cursors     []*graphics.Cursor
+	cursors     []*graphics.CursorState
 	controllers []*subControl
 	ruleset     *osu.OsuRuleSet
 	lastTime    int64
@@ -315,11 +315,14 @@ func loadFrames(subController *subControl, frames []*rplpa.ReplayData) {
 	subController.frames = frames
 }
 
-func (controller *ReplayController) InitCursors() {
+func (controller *ReplayController) InitCursors() error {
 	var modifiers []difficulty.Modifier
 	for i := range controller.controllers {
 		if controller.controllers[i].danceController != nil {
-			controller.controllers[i].danceController.InitCursors()
+			if err := controller.controllers[i].danceController.InitCursors(); err != nil {
+				return err
+			}
+
 			controller.controllers[i].danceController.GetCursors()[0].IsPlayer = true
 
 			cursors := controller.controllers[i].danceController.GetCursors()
@@ -330,7 +333,7 @@ func (controller *ReplayController) InitCursors() {
 
 			controller.cursors = append(controller.cursors, cursors...)
 		} else {
-			cursor := graphics.NewCursor()
+			cursor := graphics.NewCursorState()
 			cursor.Name = controller.replays[i].Name
 			controller.cursors = append(controller.cursors, cursor)
 		}
@@ -341,6 +344,8 @@ func (controller *ReplayController) InitCursors() {
 	controller.ruleset = osu.NewOsuRuleset(controller.bMap, controller.cursors, modifiers)
 
 	//controller.Update(480000, 1)
+
+	return nil
 }
 
 func (controller *ReplayController) Update(time int64, delta float64) {
@@ -471,10 +476,6 @@ func (controller *ReplayController) Update(time int64, delta float64) {
 	}
 
 	for i := range controller.controllers {
-		if controller.controllers[i].danceController == nil {
-			controller.cursors[i].Update(delta)
-		}
-
 		accuracy, combo, _, grade := controller.ruleset.GetResults(controller.cursors[i])
 		controller.replays[i].Accuracy = accuracy
 		controller.replays[i].Combo = combo
@@ -483,7 +484,7 @@ func (controller *ReplayController) Update(time int64, delta float64) {
 
 }
 
-func (controller *ReplayController) GetCursors() []*graphics.Cursor {
+func (controller *ReplayController) GetCursors() []*graphics.CursorState {
 	return controller.cursors
 }
 
@@ -0,0 +1,207 @@
+package dance
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/bnch/uleb128"
+	"github.com/itchio/lzma"
+	"github.com/wieku/danser-go/app/beatmap"
+	"github.com/wieku/danser-go/app/graphics"
+	"github.com/wieku/danser-go/app/settings"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Replays of danser's cursors are saved here (relative to danser's directory) when Dance.ExportReplays is enabled.
+const replayExportDir = "exported-replays"
+
+// osu! version written to exported replays, versions after 20190506 score object ends only on replay frames
+const replayVersion = 20200916
+
+// Replay frames are made at least this often (in ms), key state changes always make a frame
+const replayFrameInterval = 16
+
+const (
+	keyM1 = 1 << iota
+	keyM2
+	keyK1
+	keyK2
+)
+
+type replayFrame struct {
+	delta int64
+	x, y  float32
+	keys  int
+}
+
+type replayRecorder struct {
+	frames   []replayFrame
+	lastTime int64
+}
+
+func newReplayRecorder() *replayRecorder {
+	// osu! replays always start with these two frames
+	return &replayRecorder{
+		frames: []replayFrame{
+			{0, 256, -500, 0},
+			{-1, 256, -500, 0},
+		},
+		lastTime: -1,
+	}
+}
+
+func replayKeys(cursor *graphics.CursorState) (keys int) {
+	if cursor.LeftKey {
+		keys |= keyM1 | keyK1
+	} else if cursor.LeftMouse {
+		keys |= keyM1
+	}
+
+	if cursor.RightKey {
+		keys |= keyM2 | keyK2
+	} else if cursor.RightMouse {
+		keys |= keyM2
+	}
+
+	return
+}
+
+func (recorder *replayRecorder) record(time int64, cursor *graphics.CursorState) {
+	// Frames before the -1 marker frame can't be represented
+	if time < 0 {
+		return
+	}
+
+	recorder.frames = append(recorder.frames, replayFrame{time - recorder.lastTime, cursor.Position.X, cursor.Position.Y, replayKeys(cursor)})
+	recorder.lastTime = time
+}
+
+func (recorder *replayRecorder) encodeFrames() ([]byte, error) {
+	builder := &strings.Builder{}
+
+	for _, f := range recorder.frames {
+		builder.WriteString(strconv.FormatInt(f.delta, 10))
+		builder.WriteString("|")
+		builder.WriteString(strconv.FormatFloat(float64(f.x), 'f', -1, 32))
+		builder.WriteString("|")
+		builder.WriteString(strconv.FormatFloat(float64(f.y), 'f', -1, 32))
+		builder.WriteString("|")
+		builder.WriteString(strconv.Itoa(f.keys))
+		builder.WriteString(",")
+	}
+
+	// RNG seed frame
+	builder.WriteString("-12345|0|0|0,")
+
+	data := []byte(builder.String())
+
+	compressed := &bytes.Buffer{}
+
+	writer := lzma.NewWriterSize(compressed, int64(len(data)))
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressed.Bytes(), nil
+}
+
+type replayStats struct {
+	count300, count100, count50, countGeki, countKatu, countMiss uint16
+
+	score    int32
+	maxCombo uint16
+	perfect  bool
+	mods     uint32
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	if s == "" {
+		buf.WriteByte(0)
+		return
+	}
+
+	buf.WriteByte(11)
+	buf.Write(uleb128.Marshal(len(s)))
+	buf.WriteString(s)
+}
+
+// encodeReplay builds an .osr file, format is described at https://osu.ppy.sh/wiki/en/osu%21_File_Formats/Osr_%28file_format%29
+func encodeReplay(bMap *beatmap.BeatMap, username string, recorder *replayRecorder, stats replayStats, date time.Time) ([]byte, error) {
+	frames, err := recorder.encodeFrames()
+	if err != nil {
+		return nil, err
+	}
+
+	replayHash := md5.Sum([]byte(fmt.Sprintf("%s%s%d", bMap.MD5, username, date.UnixNano())))
+
+	buf := &bytes.Buffer{}
+
+	write := func(data interface{}) {
+		_ = binary.Write(buf, binary.LittleEndian, data)
+	}
+
+	write(int8(0))
+	write(int32(replayVersion))
+	writeString(buf, bMap.MD5)
+	writeString(buf, username)
+	writeString(buf, hex.EncodeToString(replayHash[:]))
+	write([]uint16{stats.count300, stats.count100, stats.count50, stats.countGeki, stats.countKatu, stats.countMiss})
+	write(stats.score)
+	write(stats.maxCombo)
+	write(stats.perfect)
+	write(stats.mods)
+	writeString(buf, "")
+	write(date.UnixNano()/100 + 621355968000000000) // .NET ticks
+	write(int32(len(frames)))
+	buf.Write(frames)
+	write(int64(0))
+
+	return buf.Bytes(), nil
+}
+
+var invalidFileChars = strings.NewReplacer("/", "", "\\", "", ":", "", "*", "", "?", "", "\"", "", "<", "", ">", "", "|", "")
+
+func exportReplay(bMap *beatmap.BeatMap, cursor *graphics.CursorState, index int, recorder *replayRecorder, stats replayStats) {
+	username := cursor.Name
+	if username == "" {
+		username = settings.Knockout.DanserName
+	}
+
+	date := time.Now()
+
+	data, err := encodeReplay(bMap, username, recorder, stats, date)
+	if err != nil {
+		log.Println("Failed to encode replay:", err)
+		return
+	}
+
+	// Session seed is kept in the name, .osr has no place for custom metadata
+	name := fmt.Sprintf("%s - %s - %s [%s] (cursor %d, seed %d) %s.osr", username, bMap.Artist, bMap.Name, bMap.Difficulty, index+1, settings.SEED, date.Format("2006-01-02 15-04-05"))
+
+	if err = os.MkdirAll(replayExportDir, 0755); err != nil {
+		log.Println("Failed to export replay:", err)
+		return
+	}
+
+	path := filepath.Join(replayExportDir, invalidFileChars.Replace(name))
+
+	if err = ioutil.WriteFile(path, data, 0644); err != nil {
+		log.Println("Failed to export replay:", err)
+		return
+	}
+
+	log.Println("Replay exported to", path)
+}
@@ -11,35 +11,55 @@ import (
 )
 
 type GenericScheduler struct {
-	cursor       *graphics.Cursor
+	cursor       *graphics.CursorState
 	queue        []objects.BaseObject
 	mover        movers.MultiPointMover
+	moverName    string
 	lastTime     int64
 	spinnerMover spinners.SpinnerMover
 	input        *InputProcessor
+
+	defaultMover        movers.MultiPointMover
+	defaultMoverName    string
+	defaultSpinnerMover spinners.SpinnerMover
+	timeline            *Timeline
+	tapping             *settings.Tapping
+	random              *rand.Rand
 }
 
-func NewGenericScheduler(mover func() movers.MultiPointMover) Scheduler {
-	return &GenericScheduler{mover: mover()}
+// NewGenericScheduler creates a scheduler using mover for the whole map, or only outside of timeline entries if timeline is not nil.
+func NewGenericScheduler(mover movers.MultiPointMover, moverName string, timeline *Timeline, tapping *settings.Tapping, random *rand.Rand) Scheduler {
+	return &GenericScheduler{mover: mover, moverName: moverName, defaultMover: mover, defaultMoverName: moverName, timeline: timeline, tapping: tapping, random: random}
 }
 
-func (sched *GenericScheduler) Init(objs []objects.BaseObject, cursor *graphics.Cursor, spinnerMover spinners.SpinnerMover) {
+func (sched *GenericScheduler) Init(objs []objects.BaseObject, cursor *graphics.CursorState, spinnerMover spinners.SpinnerMover) error {
 	sched.spinnerMover = spinnerMover
+	sched.defaultSpinnerMover = spinnerMover
 	sched.cursor = cursor
 	sched.queue = objs
 
-	sched.input = NewInputProcessor(objs, cursor)
+	input, err := NewInputProcessor(objs, cursor, sched.tapping)
+	if err != nil {
+		return err
+	}
+
+	sched.input = input
 
+	sched.mover = sched.defaultMover
+	sched.moverName = sched.defaultMoverName
 	sched.mover.Reset()
+	sched.timeline.reset()
 
 	for i := 0; i < len(sched.queue); i++ {
-		sched.queue = PreprocessQueue(i, sched.queue, (settings.Dance.SliderDance && !settings.Dance.RandomSliderDance) || (settings.Dance.RandomSliderDance && rand.Intn(2) == 0))
+		sched.queue = PreprocessQueue(i, sched.queue, (settings.Dance.SliderDance && !settings.Dance.RandomSliderDance) || (settings.Dance.RandomSliderDance && sched.random.Intn(2) == 0))
 	}
 
 	sched.queue = append([]objects.BaseObject{objects.DummyCircle(vector.NewVec2f(100, 100), 0)}, sched.queue...)
 
-	toRemove := sched.mover.SetObjects(sched.queue) - 1
+	toRemove := sched.setObjects(sched.queue) - 1
 	sched.queue = sched.queue[toRemove:]
+
+	return nil
 }
 
 func (sched *GenericScheduler) Update(time int64) {
@@ -58,6 +78,11 @@ func (sched *GenericScheduler) Update(time int64) {
 			if time >= g.GetBasicData().StartTime && time <= g.GetBasicData().EndTime {
 				if _, ok := g.(*objects.Spinner); ok {
 					if sched.lastTime < g.GetBasicData().StartTime {
+						sched.spinnerMover = sched.defaultSpinnerMover
+						if entry := sched.timeline.find(g.GetBasicData().StartTime); entry != nil && len(entry.spinners) > 0 {
+							sched.spinnerMover = entry.nextSpinnerMover()
+						}
+
 						sched.spinnerMover.Init(g.GetBasicData().StartTime, g.GetBasicData().EndTime)
 					}
 
@@ -68,7 +93,7 @@ func (sched *GenericScheduler) Update(time int64) {
 			} else if time > g.GetBasicData().EndTime {
 				toRemove := 1
 				if i+1 < len(sched.queue) {
-					toRemove = sched.mover.SetObjects(sched.queue[i:]) - 1
+					toRemove = sched.setObjects(sched.queue[i:]) - 1
 				}
 
 				sched.queue = append(sched.queue[:i], sched.queue[i+toRemove:]...)
@@ -87,3 +112,36 @@ func (sched *GenericScheduler) Update(time int64) {
 
 	sched.lastTime = time
 }
+
+func (sched *GenericScheduler) GetMoverName() string {
+	return sched.moverName
+}
+
+// setObjects passes objects to the mover assigned to the first movement target.
+// Objects belonging to another timeline entry are hidden from the mover, so it always ends at the last object before the boundary
+// and the next mover starts from the exact position the previous one left the cursor at.
+func (sched *GenericScheduler) setObjects(objs []objects.BaseObject) int {
+	entry := sched.timeline.find(objs[1].GetBasicData().StartTime)
+
+	mover, moverName := sched.defaultMover, sched.defaultMoverName
+	if entry != nil && entry.mover != nil {
+		mover, moverName = entry.mover, entry.moverName
+	}
+
+	if mover != sched.mover {
+		mover.Reset()
+		sched.mover = mover
+		sched.moverName = moverName
+	}
+
+	if sched.timeline == nil {
+		return mover.SetObjects(objs)
+	}
+
+	end := 2
+	for end < len(objs) && sched.timeline.find(objs[end].GetBasicData().StartTime) == entry {
+		end++
+	}
+
+	return mover.SetObjects(objs[:end])
+}
@@ -0,0 +1,251 @@
+package schedulers
+
+import (
+	"github.com/wieku/danser-go/app/beatmap/difficulty"
+	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/dance/spinners"
+	"github.com/wieku/danser-go/app/graphics"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Offsets stay this far inside the circle so rounding can't push the cursor out
+const aimNoiseLimit = 0.9
+
+// timeKnot maps real time to the time seen by the wrapped scheduler.
+type timeKnot struct {
+	real, scheduled int64
+}
+
+// aimKnot is the aim offset applied while the wrapped scheduler is between start and end.
+type aimKnot struct {
+	start, end int64
+	offset     vector.Vector2f
+}
+
+// HumanizedScheduler sits between a scheduler and the cursor. The wrapped scheduler sees warped time,
+// so objects are clicked with sampled hit errors and movements can start late, and aim offsets are added to the cursor position.
+type HumanizedScheduler struct {
+	inner  Scheduler
+	config *settings.Humanizer
+	diff   *difficulty.Difficulty
+	rng    *rand.Rand
+
+	cursor *graphics.CursorState
+
+	// Position set by the wrapped scheduler and the one after applying the offset
+	basePos, lastPos vector.Vector2f
+
+	timeKnots []timeKnot
+	aimKnots  []aimKnot
+}
+
+func NewHumanizedScheduler(inner Scheduler, diff *difficulty.Difficulty, config *settings.Humanizer, rng *rand.Rand) Scheduler {
+	return &HumanizedScheduler{inner: inner, config: config, diff: diff, rng: rng}
+}
+
+func (sched *HumanizedScheduler) Init(objs []objects.BaseObject, cursor *graphics.CursorState, spinnerMover spinners.SpinnerMover) error {
+	sched.cursor = cursor
+
+	if err := sched.inner.Init(objs, cursor, spinnerMover); err != nil {
+		return err
+	}
+
+	sorted := make([]objects.BaseObject, len(objs))
+	copy(sorted, objs)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetBasicData().StartTime < sorted[j].GetBasicData().StartTime
+	})
+
+	sched.build(sorted)
+
+	return nil
+}
+
+func (sched *HumanizedScheduler) build(objs []objects.BaseObject) {
+	sched.timeKnots = sched.timeKnots[:0]
+	sched.aimKnots = sched.aimKnots[:0]
+
+	mean := sched.config.MeanOffset
+	deviation := HitDeviation(sched.config, sched.diff)
+	limit := float64(sched.diff.Hit50 - 1)
+
+	radius := sched.diff.CircleRadius
+
+	lastReal := int64(math.MinInt64)
+	lastScheduled := int64(math.MinInt64)
+
+	for i, o := range objs {
+		data := o.GetBasicData()
+
+		// Objects overlapping the previous one (like 2B maps) just follow its time warp
+		if data.StartTime <= lastScheduled {
+			continue
+		}
+
+		offset := int64(math.Round(bmath.ClampF64(mean+sched.rng.NormFloat64()*deviation, -limit, limit)))
+
+		// Keep the time warp monotonic, objects can't be hit before the previous one ended
+		if data.StartTime+offset <= lastReal {
+			offset = lastReal + 1 - data.StartTime
+		}
+
+		if i > 0 {
+			prev := objs[i-1].GetBasicData()
+
+			if float64(prev.EndPos.Dst(data.StartPos)) > sched.config.JumpDistance && sched.rng.Float64() < sched.config.ReactionChance {
+				delay := int64(sched.rng.Float64() * sched.config.ReactionDelay)
+				delay = bmath.MinI64(delay, (data.StartTime+offset-lastReal)/2)
+
+				if delay > 0 {
+					sched.timeKnots = append(sched.timeKnots, timeKnot{lastReal + delay, prev.EndTime})
+				}
+			}
+		}
+
+		sched.timeKnots = append(sched.timeKnots, timeKnot{data.StartTime + offset, data.StartTime})
+
+		if data.EndTime > data.StartTime {
+			sched.timeKnots = append(sched.timeKnots, timeKnot{data.EndTime + offset, data.EndTime})
+		}
+
+		lastReal = data.EndTime + offset
+		lastScheduled = data.EndTime
+
+		var aim vector.Vector2f
+
+		if _, ok := o.(*objects.Spinner); !ok && sched.config.AimNoise > 0 {
+			aim = vector.NewVec2f(float32(sched.rng.NormFloat64()), float32(sched.rng.NormFloat64())).Scl(float32(sched.config.AimNoise * radius))
+
+			if maxLen := float32(radius * aimNoiseLimit); aim.Len() > maxLen {
+				aim = aim.Scl(maxLen / aim.Len())
+			}
+		}
+
+		sched.aimKnots = append(sched.aimKnots, aimKnot{data.StartTime, data.EndTime, aim})
+	}
+}
+
+// warp returns the time seen by the wrapped scheduler at real time.
+func (sched *HumanizedScheduler) warp(time int64) int64 {
+	knots := sched.timeKnots
+
+	if len(knots) == 0 {
+		return time
+	}
+
+	index := sort.Search(len(knots), func(i int) bool {
+		return knots[i].real > time
+	})
+
+	if index == 0 {
+		return time - knots[0].real + knots[0].scheduled
+	}
+
+	prev := knots[index-1]
+
+	if index == len(knots) {
+		return time - prev.real + prev.scheduled
+	}
+
+	next := knots[index]
+
+	progress := float64(time-prev.real) / float64(next.real-prev.real)
+
+	return prev.scheduled + int64(math.Round(progress*float64(next.scheduled-prev.scheduled)))
+}
+
+// aimOffset interpolates aim offsets between objects.
+func (sched *HumanizedScheduler) aimOffset(time int64) vector.Vector2f {
+	knots := sched.aimKnots
+
+	if len(knots) == 0 {
+		return vector.Vector2f{}
+	}
+
+	index := sort.Search(len(knots), func(i int) bool {
+		return knots[i].start > time
+	})
+
+	if index == 0 {
+		return knots[0].offset
+	}
+
+	prev := knots[index-1]
+
+	if time <= prev.end || index == len(knots) {
+		return prev.offset
+	}
+
+	next := knots[index]
+
+	progress := float32(time-prev.end) / float32(next.start-prev.end)
+
+	return prev.offset.Add(next.offset.Sub(prev.offset).Scl(progress))
+}
+
+func (sched *HumanizedScheduler) Update(time int64) {
+	warped := sched.warp(time)
+
+	sched.inner.Update(warped)
+
+	// Wrapped scheduler doesn't move the cursor on every update, so the offset can't be added to the current position
+	if sched.cursor.Position != sched.lastPos {
+		sched.basePos = sched.cursor.Position
+	}
+
+	sched.cursor.SetPos(sched.basePos.Add(sched.aimOffset(warped)))
+
+	sched.lastPos = sched.cursor.Position
+}
+
+func (sched *HumanizedScheduler) GetMoverName() string {
+	return sched.inner.GetMoverName()
+}
+
+// HitDeviation returns the standard deviation of hit errors in ms. If TargetAccuracy is set, it's fitted so the expected accuracy
+// of normally distributed hits within map's hit windows matches the target, otherwise it's derived from UnstableRate.
+func HitDeviation(config *settings.Humanizer, diff *difficulty.Difficulty) float64 {
+	if config.TargetAccuracy <= 0 {
+		return config.UnstableRate / 10
+	}
+
+	target := math.Min(config.TargetAccuracy, 100)
+
+	low, high := 0.01, 1000.0
+
+	for i := 0; i < 64; i++ {
+		mid := (low + high) / 2
+
+		if ExpectedAccuracy(config.MeanOffset, mid, diff) > target {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return (low + high) / 2
+}
+
+// ExpectedAccuracy returns the accuracy (in percent) of hits with normally distributed errors.
+func ExpectedAccuracy(mean, deviation float64, diff *difficulty.Difficulty) float64 {
+	within := func(window int64) float64 {
+		w := float64(window)
+		cdf := func(x float64) float64 {
+			return 0.5 * (1 + math.Erf((x-mean)/(deviation*math.Sqrt2)))
+		}
+
+		return cdf(w) - cdf(-w)
+	}
+
+	p300 := within(diff.Hit300)
+	p100 := within(diff.Hit100) - p300
+	p50 := within(diff.Hit50) - p300 - p100
+
+	return 100 * (p300*300 + p100*100 + p50*50) / 300
+}
@@ -3,12 +3,17 @@ package schedulers
 import (
 	"github.com/wieku/danser-go/app/beatmap/objects"
 	"github.com/wieku/danser-go/app/graphics"
+	"github.com/wieku/danser-go/app/settings"
 )
 
 type InputProcessor struct {
 	queue  []objects.BaseObject
-	cursor *graphics.Cursor
+	cursor *graphics.CursorState
 
+	config *settings.Tapping
+	style  TappingStyle
+
+	lastObject     objects.BaseObject
 	lastLeft       bool
 	moving         bool
 	lastEnd        int64
@@ -19,14 +24,21 @@ type InputProcessor struct {
 	rightToRelease bool
 }
 
-func NewInputProcessor(objs []objects.BaseObject, cursor *graphics.Cursor) *InputProcessor {
+func NewInputProcessor(objs []objects.BaseObject, cursor *graphics.CursorState, config *settings.Tapping) (*InputProcessor, error) {
+	style, err := NewTappingStyle(config)
+	if err != nil {
+		return nil, err
+	}
+
 	processor := new(InputProcessor)
 	processor.cursor = cursor
+	processor.config = config
+	processor.style = style
 	processor.queue = make([]objects.BaseObject, len(objs))
 
 	copy(processor.queue, objs)
 
-	return processor
+	return processor, nil
 }
 
 func (processor *InputProcessor) Update(time int64) {
@@ -40,17 +52,7 @@ func (processor *InputProcessor) Update(time int64) {
 			if time >= g.GetBasicData().StartTime && time <= g.GetBasicData().EndTime {
 				if !processor.moving {
 					if !g.GetBasicData().SliderPoint || g.GetBasicData().SliderPointStart {
-						if !processor.lastLeft && g.GetBasicData().StartTime-processor.lastEnd < 140 {
-							processor.cursor.LeftKey = true
-							processor.lastLeft = true
-							processor.leftToRelease = false
-							processor.lastLeftClick = time
-						} else {
-							processor.cursor.RightKey = true
-							processor.lastLeft = false
-							processor.rightToRelease = false
-							processor.lastRightClick = time
-						}
+						processor.press(g, time)
 					}
 				}
 
@@ -72,15 +74,77 @@ func (processor *InputProcessor) Update(time int64) {
 		}
 	}
 
-	if processor.leftToRelease && time-processor.lastLeftClick > 50 {
+	releaseTime := int64(processor.config.ReleaseTime)
+
+	if processor.leftToRelease && time-processor.lastLeftClick > releaseTime {
 		processor.leftToRelease = false
-		processor.cursor.LeftKey = false
+		processor.setLeft(false)
 	}
 
-	if processor.rightToRelease && time-processor.lastRightClick > 50 {
+	if processor.rightToRelease && time-processor.lastRightClick > releaseTime {
 		processor.rightToRelease = false
-		processor.cursor.RightKey = false
+		processor.setRight(false)
 	}
 
 	processor.lastTime = time
 }
+
+func (processor *InputProcessor) press(obj objects.BaseObject, time int64) {
+	state := keyState{
+		lastLeft:  processor.lastLeft,
+		lastEnd:   processor.lastEnd,
+		leftHeld:  processor.isLeft(),
+		rightHeld: processor.isRight(),
+	}
+
+	left := processor.style.PressLeft(processor.lastObject, obj, state)
+
+	// The chosen key can't be pressed again while it's still held
+	if left && state.leftHeld && !state.rightHeld {
+		left = false
+	} else if !left && state.rightHeld && !state.leftHeld {
+		left = true
+	}
+
+	// Sliders are released like circles if they shouldn't be held
+	_, isSlider := obj.(*objects.Slider)
+	tapped := (isSlider || obj.GetBasicData().SliderPoint) && !processor.config.HoldSliders
+
+	if left {
+		processor.setLeft(true)
+		processor.lastLeft = true
+		processor.leftToRelease = tapped
+		processor.lastLeftClick = time
+	} else {
+		processor.setRight(true)
+		processor.lastLeft = false
+		processor.rightToRelease = tapped
+		processor.lastRightClick = time
+	}
+
+	processor.lastObject = obj
+}
+
+func (processor *InputProcessor) setLeft(pressed bool) {
+	if processor.config.MouseButtons {
+		processor.cursor.LeftMouse = pressed
+	} else {
+		processor.cursor.LeftKey = pressed
+	}
+}
+
+func (processor *InputProcessor) setRight(pressed bool) {
+	if processor.config.MouseButtons {
+		processor.cursor.RightMouse = pressed
+	} else {
+		processor.cursor.RightKey = pressed
+	}
+}
+
+func (processor *InputProcessor) isLeft() bool {
+	return processor.cursor.LeftKey || processor.cursor.LeftMouse
+}
+
+func (processor *InputProcessor) isRight() bool {
+	return processor.cursor.RightKey || processor.cursor.RightMouse
+}
@@ -7,6 +7,9 @@ import (
 )
 
 type Scheduler interface {
-	Init(objects []objects.BaseObject, cursor *graphics.Cursor, spinnerMover spinners.SpinnerMover)
+	Init(objects []objects.BaseObject, cursor *graphics.CursorState, spinnerMover spinners.SpinnerMover) error
 	Update(time int64)
+
+	// GetMoverName returns the name of mover currently moving the cursor between objects
+	GetMoverName() string
 }
@@ -0,0 +1,82 @@
+package schedulers
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/beatmap/objects"
+	"github.com/wieku/danser-go/app/settings"
+	"strings"
+)
+
+// keyState is the InputProcessor's state visible to tapping styles.
+type keyState struct {
+	lastLeft  bool  // whether the last press used the left key
+	lastEnd   int64 // end time of the last pressed object
+	leftHeld  bool
+	rightHeld bool
+}
+
+// TappingStyle chooses the key used to press an object. prev is the previously pressed object, nil for the first one.
+type TappingStyle interface {
+	PressLeft(prev, obj objects.BaseObject, state keyState) bool
+}
+
+type defaultTapping struct{}
+
+func (defaultTapping) PressLeft(_, obj objects.BaseObject, state keyState) bool {
+	return !state.lastLeft && obj.GetBasicData().StartTime-state.lastEnd < 140
+}
+
+type alternateTapping struct{}
+
+func (alternateTapping) PressLeft(_, _ objects.BaseObject, state keyState) bool {
+	return !state.lastLeft
+}
+
+type singleTapping struct{}
+
+func (singleTapping) PressLeft(_, _ objects.BaseObject, _ keyState) bool {
+	return true
+}
+
+type thresholdTapping struct {
+	interval float64
+	spacing  float64
+}
+
+func (style thresholdTapping) PressLeft(prev, obj objects.BaseObject, state keyState) bool {
+	if prev == nil {
+		return true
+	}
+
+	prevData, data := prev.GetBasicData(), obj.GetBasicData()
+
+	// 1ms of leeway for rounded object times
+	alternate := float64(data.StartTime-prevData.StartTime) <= style.interval+1
+	alternate = alternate || (style.spacing > 0 && float64(prevData.EndPos.Dst(data.StartPos)) >= style.spacing)
+
+	if alternate {
+		return !state.lastLeft
+	}
+
+	return true
+}
+
+// NewTappingStyle returns the style described by config.Style.
+func NewTappingStyle(config *settings.Tapping) (TappingStyle, error) {
+	switch strings.ToLower(config.Style) {
+	case "", "default":
+		return defaultTapping{}, nil
+	case "alternate":
+		return alternateTapping{}, nil
+	case "singletap":
+		return singleTapping{}, nil
+	case "threshold":
+		if config.ThresholdBPM <= 0 {
+			return nil, fmt.Errorf("threshold tapping style requires ThresholdBPM above 0")
+		}
+
+		return thresholdTapping{interval: 15000 / config.ThresholdBPM, spacing: config.ThresholdSpacing}, nil
+	}
+
+	return nil, fmt.Errorf("unknown tapping style \"%s\", available styles: default, alternate, singletap, threshold", config.Style)
+}
@@ -0,0 +1,193 @@
+package schedulers
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/beatmap"
+	"github.com/wieku/danser-go/app/dance/movers"
+	"github.com/wieku/danser-go/app/dance/spinners"
+	"github.com/wieku/danser-go/app/settings"
+	"math"
+	"strings"
+)
+
+type timeRange struct {
+	start, end int64
+}
+
+type timelineEntry struct {
+	ranges []timeRange
+
+	mover     movers.MultiPointMover
+	moverName string
+
+	spinners     []spinners.SpinnerMover
+	spinnerIndex int
+}
+
+func (entry *timelineEntry) contains(time int64) bool {
+	for _, r := range entry.ranges {
+		if time >= r.start && time < r.end {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (entry *timelineEntry) nextSpinnerMover() spinners.SpinnerMover {
+	mover := entry.spinners[entry.spinnerIndex%len(entry.spinners)]
+	entry.spinnerIndex++
+
+	return mover
+}
+
+// Timeline assigns movers to parts of the map. Every cursor needs its own Timeline because movers keep state.
+type Timeline struct {
+	entries []*timelineEntry
+}
+
+func NewTimeline(bMap *beatmap.BeatMap, config []*settings.TimelineEntry) (*Timeline, error) {
+	timeline := &Timeline{}
+
+	for i, e := range config {
+		entry := &timelineEntry{}
+
+		switch strings.ToLower(e.Type) {
+		case "time":
+			end := e.EndTime
+			if end == 0 {
+				end = math.MaxInt64
+			}
+
+			entry.ranges = []timeRange{{e.StartTime, end}}
+		case "kiai":
+			entry.ranges = kiaiRanges(bMap)
+		case "section":
+			r, ok := sectionRange(bMap, e.Section)
+			if !ok {
+				continue
+			}
+
+			entry.ranges = []timeRange{r}
+		default:
+			return nil, unknownTypeError(i, e)
+		}
+
+		if e.Mover != nil {
+			mover, err := movers.Create(e.Mover)
+			if err != nil {
+				return nil, fmt.Errorf("timeline entry %d: %w", i, err)
+			}
+
+			if m, ok := mover.(movers.TimingMover); ok {
+				m.SetTimings(bMap.Timings)
+			}
+
+			entry.mover = mover
+			entry.moverName = e.Mover.GetName()
+		}
+
+		for _, s := range e.Spinners {
+			mover, err := spinners.Create(s)
+			if err != nil {
+				return nil, fmt.Errorf("timeline entry %d: %w", i, err)
+			}
+
+			entry.spinners = append(entry.spinners, mover)
+		}
+
+		timeline.entries = append(timeline.entries, entry)
+	}
+
+	return timeline, nil
+}
+
+// ValidateTimeline checks the parts of timeline config that don't depend on the beatmap.
+func ValidateTimeline(config []*settings.TimelineEntry) error {
+	for i, e := range config {
+		switch strings.ToLower(e.Type) {
+		case "time", "kiai", "section":
+		default:
+			return unknownTypeError(i, e)
+		}
+	}
+
+	return nil
+}
+
+func unknownTypeError(i int, e *settings.TimelineEntry) error {
+	return fmt.Errorf("timeline entry %d: unknown type \"%s\", expected time, kiai or section", i, e.Type)
+}
+
+// find returns the first entry containing given time, nil if there's none.
+func (timeline *Timeline) find(time int64) *timelineEntry {
+	if timeline == nil {
+		return nil
+	}
+
+	for _, entry := range timeline.entries {
+		if entry.contains(time) {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+func (timeline *Timeline) reset() {
+	if timeline == nil {
+		return
+	}
+
+	for _, entry := range timeline.entries {
+		entry.spinnerIndex = 0
+
+		if entry.mover != nil {
+			entry.mover.Reset()
+		}
+	}
+}
+
+func kiaiRanges(bMap *beatmap.BeatMap) (ranges []timeRange) {
+	inKiai := false
+
+	for _, p := range bMap.Timings.Points {
+		if p.Kiai == inKiai {
+			continue
+		}
+
+		if p.Kiai {
+			ranges = append(ranges, timeRange{p.Time, math.MaxInt64})
+		} else {
+			ranges[len(ranges)-1].end = p.Time
+		}
+
+		inKiai = p.Kiai
+	}
+
+	return
+}
+
+func sectionRange(bMap *beatmap.BeatMap, section int) (timeRange, bool) {
+	count := len(bMap.Pauses) + 1
+
+	if section < 0 {
+		section += count
+	}
+
+	if section < 0 || section >= count {
+		return timeRange{}, false
+	}
+
+	r := timeRange{math.MinInt64, math.MaxInt64}
+
+	if section > 0 {
+		r.start = bMap.Pauses[section-1].GetBasicData().EndTime
+	}
+
+	if section < count-1 {
+		r.end = bMap.Pauses[section].GetBasicData().StartTime
+	}
+
+	return r, true
+}
@@ -2,7 +2,6 @@ package spinners
 
 import (
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/wieku/danser-go/app/settings"
 	"github.com/wieku/danser-go/framework/math/math32"
 	"github.com/wieku/danser-go/framework/math/vector"
 )
@@ -21,11 +20,12 @@ var cubeVertices = []mgl32.Vec4{
 var cubeIndices = []int{0, 1, 2, 3, 0, 4, 5, 1, 5, 6, 2, 6, 7, 3, 7, 4}
 
 type CubeMover struct {
-	start int64
+	start  int64
+	config *Config
 }
 
-func NewCubeMover() *CubeMover {
-	return &CubeMover{}
+func NewCubeMover(config *Config) *CubeMover {
+	return &CubeMover{config: config}
 }
 
 func (c *CubeMover) Init(start, end int64) {
@@ -36,7 +36,7 @@ func (c *CubeMover) GetPositionAt(time int64) vector.Vector2f {
 	radY := math32.Sin(float32(time-c.start)/9000*2*math32.Pi) * 3.0 / 18 * math32.Pi
 	radX := math32.Sin(float32(time-c.start)/5000*2*math32.Pi) * 3.0 / 18 * math32.Pi
 
-	scale := (1.0 + math32.Sin(float32(time-c.start)/4500*2*math32.Pi)*0.3) * float32(settings.Dance.SpinnerRadius)
+	scale := (1.0 + math32.Sin(float32(time-c.start)/4500*2*math32.Pi)*0.3) * float32(c.config.Radius)
 
 	mat := mgl32.HomogRotate3DY(radY).Mul4(mgl32.HomogRotate3DX(radX)).Mul4(mgl32.Scale3D(scale, scale, scale))
 
@@ -2,29 +2,17 @@ package spinners
 
 import (
 	"github.com/wieku/danser-go/framework/math/vector"
-	"strings"
 )
 
+// Rotations per millisecond, it's the highest spinner velocity counted by OsuRuleSet (0.05 rad/ms)
 const rpms = 0.00795
 
+// RPM needed to clear a spinner with a 300 on OD 10
+const requiredRPM = 7.5 / 2 * 60
+
 var center = vector.NewVec2f(256, 192)
 
 type SpinnerMover interface {
 	Init(start, end int64)
 	GetPositionAt(time int64) vector.Vector2f
 }
-
-func GetMoverByName(name string) SpinnerMover {
-	switch strings.ToLower(name) {
-	case "heart":
-		return NewHeartMover()
-	case "triangle":
-		return NewTriangleMover()
-	case "square":
-		return NewSquareMover()
-	case "cube":
-		return NewCubeMover()
-	default:
-		return NewCircleMover()
-	}
-}
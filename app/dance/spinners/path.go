@@ -0,0 +1,368 @@
+package spinners
+
+import (
+	"errors"
+	"fmt"
+	"github.com/wieku/danser-go/framework/math/math32"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const curveSegments = 16
+
+var svgPathRegex = regexp.MustCompile(`<path[^>]*\sd\s*=\s*"([^"]*)"`)
+var pathTokenRegex = regexp.MustCompile(`[a-zA-Z]|[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+type PathConfig struct {
+	Radius float64
+
+	// Path of a file with the shape: an .svg file (first <path> element is used), raw SVG path data or a list of "x,y" points.
+	// Supported path commands are M, L, H, V, C, Q and Z. The shape is centered at its centroid and scaled to Radius.
+	File string
+}
+
+// PathMover follows a closed shape loaded from a file with constant angular velocity around the spinner.
+type PathMover struct {
+	start  int64
+	config *PathConfig
+
+	points []vector.Vector2f
+	angles []float32
+	deltas []float32
+
+	// accumulated absolute angle at each point
+	lengths []float32
+}
+
+func NewPathMover(config *PathConfig) (*PathMover, error) {
+	if config.File == "" {
+		return nil, errors.New("File has to be set")
+	}
+
+	data, err := ioutil.ReadFile(config.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []vector.Vector2f
+
+	content := strings.TrimSpace(string(data))
+
+	if strings.EqualFold(filepath.Ext(config.File), ".svg") {
+		match := svgPathRegex.FindStringSubmatch(content)
+		if match == nil {
+			return nil, fmt.Errorf("%s doesn't contain any <path> element", config.File)
+		}
+
+		points, err = parsePathData(match[1])
+	} else if len(content) > 0 && (content[0] >= 'a' && content[0] <= 'z' || content[0] >= 'A' && content[0] <= 'Z') {
+		points, err = parsePathData(content)
+	} else {
+		points, err = parsePointList(content)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", config.File, err)
+	}
+
+	mover := &PathMover{config: config}
+
+	if err = mover.setShape(points); err != nil {
+		return nil, fmt.Errorf("%s: %w", config.File, err)
+	}
+
+	return mover, nil
+}
+
+func (c *PathMover) setShape(points []vector.Vector2f) error {
+	if len(points) < 3 {
+		return errors.New("path needs at least 3 points")
+	}
+
+	if points[0] != points[len(points)-1] {
+		points = append(points, points[0])
+	}
+
+	middle := shapeCentroid(points)
+
+	var maxDist float32
+	for _, p := range points {
+		maxDist = math32.Max(maxDist, p.Dst(middle))
+	}
+
+	if maxDist == 0 {
+		return errors.New("path is a single point")
+	}
+
+	c.points = c.points[:0]
+	c.angles = c.angles[:0]
+	c.deltas = c.deltas[:0]
+	c.lengths = c.lengths[:0]
+
+	var length, winding float32
+
+	for _, p := range points {
+		p = p.Sub(middle).Scl(float32(c.config.Radius) / maxDist)
+
+		// points in the center don't have an angle
+		if p.Len() < 0.001 {
+			continue
+		}
+
+		angle := p.AngleR()
+
+		var delta float32
+
+		if len(c.points) > 0 {
+			delta = angle - c.angles[len(c.angles)-1]
+
+			if delta > math32.Pi {
+				delta -= 2 * math32.Pi
+			} else if delta < -math32.Pi {
+				delta += 2 * math32.Pi
+			}
+
+			length += math32.Abs(delta)
+			winding += delta
+		}
+
+		c.points = append(c.points, p)
+		c.angles = append(c.angles, angle)
+		c.deltas = append(c.deltas, delta)
+		c.lengths = append(c.lengths, length)
+	}
+
+	if math32.Abs(winding) < math32.Pi {
+		return errors.New("path has to go around its center")
+	}
+
+	// turning back around the center slows the spinner down
+	if math32.Abs(winding)/length*rpms*60000 < requiredRPM {
+		return fmt.Errorf("path turns back too much, it would spin at %.0f RPM but at least %.0f RPM is needed", math32.Abs(winding)/length*rpms*60000, requiredRPM)
+	}
+
+	return nil
+}
+
+func (c *PathMover) Init(start, end int64) {
+	c.start = start
+}
+
+func (c *PathMover) GetPositionAt(time int64) vector.Vector2f {
+	total := c.lengths[len(c.lengths)-1]
+
+	target := math32.Mod(rpms*float32(time-c.start)*2*math32.Pi, total)
+
+	index := sort.Search(len(c.lengths), func(i int) bool {
+		return c.lengths[i] > target
+	})
+
+	if index == 0 {
+		return c.points[0].Add(center)
+	} else if index >= len(c.points) {
+		return c.points[len(c.points)-1].Add(center)
+	}
+
+	pt1 := c.points[index-1]
+	pt2 := c.points[index]
+
+	sign := float32(1)
+	if c.deltas[index] < 0 {
+		sign = -1
+	}
+
+	dir := vector.NewVec2fRad(c.angles[index-1]+sign*(target-c.lengths[index-1]), 1)
+	edge := pt2.Sub(pt1)
+
+	denominator := dir.X*edge.Y - dir.Y*edge.X
+	if math32.Abs(denominator) < 0.00001 {
+		return pt1.Add(center)
+	}
+
+	return dir.Scl((pt1.X*edge.Y - pt1.Y*edge.X) / denominator).Add(center)
+}
+
+// shapeCentroid returns the centroid of a closed polygon, or the middle of its bounds if the polygon has no area.
+func shapeCentroid(points []vector.Vector2f) vector.Vector2f {
+	var area float32
+	var centroid vector.Vector2f
+
+	for i := 0; i < len(points)-1; i++ {
+		cross := points[i].X*points[i+1].Y - points[i+1].X*points[i].Y
+
+		area += cross
+		centroid = centroid.Add(points[i].Add(points[i+1]).Scl(cross))
+	}
+
+	if math32.Abs(area) > 0.0001 {
+		return centroid.Scl(1 / (3 * area))
+	}
+
+	minP, maxP := points[0], points[0]
+
+	for _, p := range points {
+		minP = vector.NewVec2f(math32.Min(minP.X, p.X), math32.Min(minP.Y, p.Y))
+		maxP = vector.NewVec2f(math32.Max(maxP.X, p.X), math32.Max(maxP.Y, p.Y))
+	}
+
+	return minP.Mid(maxP)
+}
+
+func parsePointList(content string) ([]vector.Vector2f, error) {
+	fields := strings.FieldsFunc(content, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	if len(fields)%2 != 0 {
+		return nil, errors.New("odd number of coordinates")
+	}
+
+	points := make([]vector.Vector2f, 0, len(fields)/2)
+
+	for i := 0; i < len(fields); i += 2 {
+		x, err := strconv.ParseFloat(fields[i], 32)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := strconv.ParseFloat(fields[i+1], 32)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, vector.NewVec2f(float32(x), float32(y)))
+	}
+
+	return points, nil
+}
+
+func parsePathData(data string) ([]vector.Vector2f, error) {
+	tokens := pathTokenRegex.FindAllString(data, -1)
+
+	var points []vector.Vector2f
+	var current, subpathStart vector.Vector2f
+
+	command := ""
+
+	index := 0
+
+	next := func(count int) ([]float32, error) {
+		if index+count > len(tokens) {
+			return nil, fmt.Errorf("command %s is missing coordinates", command)
+		}
+
+		values := make([]float32, count)
+
+		for i := range values {
+			value, err := strconv.ParseFloat(tokens[index+i], 32)
+			if err != nil {
+				return nil, fmt.Errorf("command %s: %w", command, err)
+			}
+
+			values[i] = float32(value)
+		}
+
+		index += count
+
+		return values, nil
+	}
+
+	for index < len(tokens) {
+		token := tokens[index]
+
+		if (token[0] >= 'a' && token[0] <= 'z') || (token[0] >= 'A' && token[0] <= 'Z') {
+			command = token
+			index++
+		} else if command == "" {
+			return nil, errors.New("coordinates without a command")
+		}
+
+		relative := strings.ToLower(command) == command
+
+		var offset vector.Vector2f
+		if relative {
+			offset = current
+		}
+
+		switch strings.ToUpper(command) {
+		case "M", "L":
+			v, err := next(2)
+			if err != nil {
+				return nil, err
+			}
+
+			current = vector.NewVec2f(v[0], v[1]).Add(offset)
+
+			if strings.ToUpper(command) == "M" {
+				subpathStart = current
+
+				// next coordinate pairs are implicit line commands
+				if relative {
+					command = "l"
+				} else {
+					command = "L"
+				}
+			}
+
+			points = append(points, current)
+		case "H":
+			v, err := next(1)
+			if err != nil {
+				return nil, err
+			}
+
+			current.X = v[0] + offset.X
+			points = append(points, current)
+		case "V":
+			v, err := next(1)
+			if err != nil {
+				return nil, err
+			}
+
+			current.Y = v[0] + offset.Y
+			points = append(points, current)
+		case "Q":
+			v, err := next(4)
+			if err != nil {
+				return nil, err
+			}
+
+			p0, p1, p2 := current, vector.NewVec2f(v[0], v[1]).Add(offset), vector.NewVec2f(v[2], v[3]).Add(offset)
+
+			for i := 1; i <= curveSegments; i++ {
+				t := float32(i) / curveSegments
+				points = append(points, p0.Scl((1-t)*(1-t)).Add(p1.Scl(2*(1-t)*t)).Add(p2.Scl(t*t)))
+			}
+
+			current = p2
+		case "C":
+			v, err := next(6)
+			if err != nil {
+				return nil, err
+			}
+
+			p0, p1, p2, p3 := current, vector.NewVec2f(v[0], v[1]).Add(offset), vector.NewVec2f(v[2], v[3]).Add(offset), vector.NewVec2f(v[4], v[5]).Add(offset)
+
+			for i := 1; i <= curveSegments; i++ {
+				t := float32(i) / curveSegments
+				it := 1 - t
+				points = append(points, p0.Scl(it*it*it).Add(p1.Scl(3*it*it*t)).Add(p2.Scl(3*it*t*t)).Add(p3.Scl(t*t*t)))
+			}
+
+			current = p3
+		case "Z":
+			current = subpathStart
+			command = ""
+			points = append(points, current)
+		default:
+			return nil, fmt.Errorf("unsupported path command %s", command)
+		}
+	}
+
+	return points, nil
+}
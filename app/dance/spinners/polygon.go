@@ -0,0 +1,79 @@
+package spinners
+
+import (
+	"errors"
+	"github.com/wieku/danser-go/framework/math/math32"
+	"github.com/wieku/danser-go/framework/math/vector"
+)
+
+type PolygonConfig struct {
+	Radius float64
+
+	// Number of polygon's corners
+	Sides int
+
+	// Distance of inner corners from the center relative to Radius, 0 makes a regular polygon and values above make a star
+	StarRatio float64
+}
+
+// PolygonMover traces a polygon or a star, the cursor moves along the edges with constant angular velocity around the spinner.
+type PolygonMover struct {
+	start    int64
+	config   *PolygonConfig
+	vertices []vector.Vector2f
+}
+
+func NewPolygonMover(config *PolygonConfig) (*PolygonMover, error) {
+	if config.Radius <= 0 {
+		return nil, errors.New("Radius has to be greater than 0")
+	}
+
+	if config.Sides < 3 {
+		return nil, errors.New("Sides has to be at least 3")
+	}
+
+	if config.StarRatio < 0 || config.StarRatio >= 1 {
+		return nil, errors.New("StarRatio has to be between 0 and 1")
+	}
+
+	count := config.Sides
+	if config.StarRatio > 0 {
+		count *= 2
+	}
+
+	vertices := make([]vector.Vector2f, count)
+
+	for i := range vertices {
+		radius := float32(config.Radius)
+		if i%2 == 1 && config.StarRatio > 0 {
+			radius *= float32(config.StarRatio)
+		}
+
+		vertices[i] = vector.NewVec2fRad(float32(i)/float32(count)*2*math32.Pi-math32.Pi/2, radius)
+	}
+
+	return &PolygonMover{config: config, vertices: vertices}, nil
+}
+
+func (c *PolygonMover) Init(start, end int64) {
+	c.start = start
+}
+
+func (c *PolygonMover) GetPositionAt(time int64) vector.Vector2f {
+	step := 2 * math32.Pi / float32(len(c.vertices))
+
+	// vertices are spread evenly by angle, so the edge is picked by the angle and the cursor is put where the ray crosses it
+	angle := rpms * float32(time-c.start) * 2 * math32.Pi
+
+	index := int(angle/step) % len(c.vertices)
+
+	pt1 := c.vertices[index]
+	pt2 := c.vertices[(index+1)%len(c.vertices)]
+
+	dir := vector.NewVec2fRad(angle-math32.Pi/2, 1)
+	edge := pt2.Sub(pt1)
+
+	t := (pt1.X*edge.Y - pt1.Y*edge.X) / (dir.X*edge.Y - dir.Y*edge.X)
+
+	return dir.Scl(t).Add(center)
+}
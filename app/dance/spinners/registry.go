@@ -0,0 +1,153 @@
+package spinners
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/settings"
+	"sort"
+	"strings"
+)
+
+// Config is the parameter block shared by built-in spinner movers.
+type Config struct {
+	Radius float64
+}
+
+func newConfig() interface{} {
+	return &Config{Radius: settings.Dance.SpinnerRadius}
+}
+
+type Registration struct {
+	Name string
+
+	// NewConfig returns a fresh parameter block filled with default values, it's nil if the mover has no parameters
+	NewConfig func() interface{}
+
+	// Create returns a new spinner mover using the parameter block from NewConfig, or an error if the parameters are invalid
+	Create func(config interface{}) (SpinnerMover, error)
+}
+
+var registry = make(map[string]*Registration)
+
+func init() {
+	Register(&Registration{
+		Name:      "circle",
+		NewConfig: newConfig,
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewCircleMover(config.(*Config)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name:      "heart",
+		NewConfig: newConfig,
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewHeartMover(config.(*Config)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name:      "triangle",
+		NewConfig: newConfig,
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewTriangleMover(config.(*Config)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name:      "square",
+		NewConfig: newConfig,
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewSquareMover(config.(*Config)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name:      "cube",
+		NewConfig: newConfig,
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewCubeMover(config.(*Config)), nil
+		},
+	})
+
+	Register(&Registration{
+		Name: "polygon",
+		NewConfig: func() interface{} {
+			return &PolygonConfig{Radius: settings.Dance.SpinnerRadius, Sides: 5}
+		},
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewPolygonMover(config.(*PolygonConfig))
+		},
+	})
+
+	Register(&Registration{
+		Name: "spiral",
+		NewConfig: func() interface{} {
+			return &SpiralConfig{Radius: settings.Dance.SpinnerRadius, StartRadius: settings.Dance.SpinnerRadius / 5}
+		},
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewSpiralMover(config.(*SpiralConfig))
+		},
+	})
+
+	Register(&Registration{
+		Name: "path",
+		NewConfig: func() interface{} {
+			return &PathConfig{Radius: settings.Dance.SpinnerRadius}
+		},
+		Create: func(config interface{}) (SpinnerMover, error) {
+			return NewPathMover(config.(*PathConfig))
+		},
+	})
+}
+
+// Register makes a spinner mover available under the given name in Dance.Spinners.
+func Register(registration *Registration) {
+	name := strings.ToLower(registration.Name)
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("spinner mover \"%s\" is already registered", name))
+	}
+
+	registry[name] = registration
+}
+
+// Names returns sorted names of all registered spinner movers.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Create builds a spinner mover described by a settings entry, its parameter block is applied over mover's defaults.
+func Create(entry *settings.Mover) (SpinnerMover, error) {
+	registration, ok := registry[entry.GetName()]
+	if !ok {
+		return nil, fmt.Errorf("unknown spinner mover \"%s\", available spinner movers: %s", entry.Name, strings.Join(Names(), ", "))
+	}
+
+	var config interface{}
+	if registration.NewConfig != nil {
+		config = registration.NewConfig()
+	}
+
+	if len(entry.Config) > 0 && config == nil {
+		return nil, fmt.Errorf("spinner mover \"%s\" doesn't have any parameters", entry.Name)
+	}
+
+	if err := entry.DecodeConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid parameters for spinner mover \"%s\": %w", entry.Name, err)
+	}
+
+	mover, err := registration.Create(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for spinner mover \"%s\": %w", entry.Name, err)
+	}
+
+	return mover, nil
+}
@@ -0,0 +1,45 @@
+package spinners
+
+import (
+	"errors"
+	"github.com/wieku/danser-go/framework/math/math32"
+	"github.com/wieku/danser-go/framework/math/vector"
+)
+
+type SpiralConfig struct {
+	// Radius reached at the end of the spinner
+	Radius float64
+
+	// Radius at the start of the spinner
+	StartRadius float64
+}
+
+// SpiralMover draws an Archimedean spiral, its radius grows linearly over the spinner duration.
+type SpiralMover struct {
+	start, end int64
+	config     *SpiralConfig
+}
+
+func NewSpiralMover(config *SpiralConfig) (*SpiralMover, error) {
+	if config.StartRadius <= 0 || config.Radius <= 0 {
+		return nil, errors.New("Radius and StartRadius have to be above 0")
+	}
+
+	return &SpiralMover{config: config}, nil
+}
+
+func (c *SpiralMover) Init(start, end int64) {
+	c.start = start
+	c.end = end
+}
+
+func (c *SpiralMover) GetPositionAt(time int64) vector.Vector2f {
+	progress := float32(1.0)
+	if c.end > c.start {
+		progress = math32.Min(math32.Max(float32(time-c.start)/float32(c.end-c.start), 0), 1)
+	}
+
+	radius := float32(c.config.StartRadius) + float32(c.config.Radius-c.config.StartRadius)*progress
+
+	return vector.NewVec2fRad(rpms*float32(time-c.start)*2*math32.Pi, radius).Add(center)
+}
@@ -2,7 +2,6 @@ package spinners
 
 import (
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/wieku/danser-go/app/settings"
 	"github.com/wieku/danser-go/framework/math/math32"
 	"github.com/wieku/danser-go/framework/math/vector"
 )
@@ -10,11 +9,12 @@ import (
 var indices = []mgl32.Vec3{{-1, -1, 0}, {1, -1, 0}, {1, 1, 0}, {-1, 1, 0}}
 
 type SquareMover struct {
-	start int64
+	start  int64
+	config *Config
 }
 
-func NewSquareMover() *SquareMover {
-	return &SquareMover{}
+func NewSquareMover(config *Config) *SquareMover {
+	return &SquareMover{config: config}
 }
 
 func (c *SquareMover) Init(start, end int64) {
@@ -22,7 +22,7 @@ func (c *SquareMover) Init(start, end int64) {
 }
 
 func (c *SquareMover) GetPositionAt(time int64) vector.Vector2f {
-	mat := mgl32.Rotate3DZ(float32(time-c.start) / 2000 * 2 * math32.Pi).Mul3(mgl32.Scale2D(float32(settings.Dance.SpinnerRadius), float32(settings.Dance.SpinnerRadius)))
+	mat := mgl32.Rotate3DZ(float32(time-c.start) / 2000 * 2 * math32.Pi).Mul3(mgl32.Scale2D(float32(c.config.Radius), float32(c.config.Radius)))
 
 	startIndex := ((time - c.start) / 10) % 4
 
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"github.com/wieku/danser-go/app/utils"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type ImportResult struct {
+	Archive string
+
+	// Name of the beatmap set directory inside OsuSongsDir, empty if nothing was extracted
+	Dir string
+
+	// Whether the archive was merged into an already existing beatmap set
+	Merged bool
+
+	Files  []string
+	Errors []error
+}
+
+func (result *ImportResult) Failed() bool {
+	return len(result.Errors) > 0
+}
+
+// ImportArchive safely extracts a beatmap set archive (.osz) into OsuSongsDir.
+// Archives containing difficulties that are already known (by MD5) are merged into the existing set instead of creating a duplicate.
+// The archive is deleted on success and quarantined otherwise.
+func ImportArchive(osPathname string) (result *ImportResult) {
+	result = &ImportResult{Archive: osPathname}
+
+	defer func() {
+		for _, err := range result.Errors {
+			log.Println("Import error:", filepath.Base(osPathname)+":", err)
+		}
+
+		if result.Failed() {
+			utils.QuarantineArchive(result.Archive, result.Errors)
+		} else if err := os.Remove(osPathname); err != nil {
+			log.Println("Failed to remove imported archive:", err)
+		}
+	}()
+
+	songsDir := filepath.Dir(osPathname)
+
+	r, err := zip.OpenReader(osPathname)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return
+	}
+
+	defer r.Close()
+
+	// Validation doesn't depend on the final directory, so it's done before anything touches the disk
+	if errs := utils.ValidateArchive(&r.Reader, filepath.Join(songsDir, "archive")); len(errs) > 0 {
+		result.Errors = errs
+		return
+	}
+
+	hashes, errs := archiveHashes(&r.Reader)
+	if len(errs) > 0 {
+		result.Errors = errs
+		return
+	}
+
+	if len(hashes) == 0 {
+		result.Errors = append(result.Errors, fmt.Errorf("archive doesn't contain any .osu files"))
+		return
+	}
+
+	dir, merged := findTargetDir(songsDir, strings.TrimSuffix(filepath.Base(osPathname), filepath.Ext(osPathname)), hashes)
+
+	result.Dir = dir
+	result.Merged = merged
+
+	if merged {
+		log.Println("Merging", osPathname, "into existing beatmap set", dir)
+	} else {
+		log.Println("Unpacking", osPathname, "to", dir)
+	}
+
+	result.Files, result.Errors = utils.ExtractArchive(&r.Reader, filepath.Join(songsDir, dir))
+
+	return
+}
+
+// archiveHashes returns MD5 hashes of all .osu files in the archive.
+func archiveHashes(r *zip.Reader) (hashes []string, errs []error) {
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(f.Name), ".osu") {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			errs = append(errs, &utils.ArchiveError{File: f.Name, Err: err})
+			continue
+		}
+
+		hash := md5.New()
+		_, err = io.Copy(hash, rc)
+
+		rc.Close()
+
+		if err != nil {
+			errs = append(errs, &utils.ArchiveError{File: f.Name, Err: err})
+			continue
+		}
+
+		hashes = append(hashes, hex.EncodeToString(hash.Sum(nil)))
+	}
+
+	return
+}
+
+// findTargetDir looks for an existing beatmap set sharing at least one difficulty with the archive, first in the database, then on disk.
+// If there is none, a free directory name based on the archive name is returned.
+func findTargetDir(songsDir, name string, hashes []string) (dir string, merged bool) {
+	known := make(map[string]bool)
+	for _, h := range hashes {
+		known[h] = true
+	}
+
+	if dbFile != nil {
+		for _, h := range hashes {
+			var existing string
+			if err := dbFile.QueryRow("SELECT dir FROM beatmaps WHERE md5 = ?", h).Scan(&existing); err == nil {
+				if stat, err := os.Stat(filepath.Join(songsDir, existing)); err == nil && stat.IsDir() {
+					return existing, true
+				}
+			}
+		}
+	}
+
+	dir = name
+
+	for i := 2; ; i++ {
+		stat, err := os.Stat(filepath.Join(songsDir, dir))
+		if os.IsNotExist(err) {
+			return dir, false
+		}
+
+		if err == nil && stat.IsDir() && containsAnyHash(filepath.Join(songsDir, dir), known) {
+			return dir, true
+		}
+
+		dir = fmt.Sprintf("%s (%d)", name, i)
+	}
+}
+
+func containsAnyHash(path string, hashes map[string]bool) bool {
+	files, _ := ioutil.ReadDir(path)
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(strings.ToLower(f.Name()), ".osu") {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(path, f.Name()))
+		if err != nil {
+			continue
+		}
+
+		sum := md5.Sum(data)
+		if hashes[hex.EncodeToString(sum[:])] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -150,9 +150,7 @@ func LoadBeatmaps() []*beatmap.BeatMap {
 			}
 
 			if strings.HasSuffix(de.Name(), ".osz") {
-				log.Println("Unpacking", osPathname, "to", filepath.Dir(osPathname)+"/"+strings.TrimSuffix(de.Name(), ".osz"))
-				utils.Unzip(osPathname, filepath.Dir(osPathname)+"/"+strings.TrimSuffix(de.Name(), ".osz"))
-				os.Remove(osPathname)
+				ImportArchive(osPathname)
 			}
 
 			return nil
@@ -184,22 +182,8 @@ func LoadBeatmaps() []*beatmap.BeatMap {
 						log.Println("New beatmap found:", de.Name())
 					}
 
-					file, err := os.Open(osPathname)
-
-					if err == nil {
-						defer file.Close()
-
-						if bMap := beatmap.ParseBeatMapFile(file); bMap != nil {
-							bMap.LastModified = stat.ModTime().UnixNano() / 1000000
-							bMap.TimeAdded = time.Now().UnixNano() / 1000000
-							log.Println("Importing:", bMap.File)
-
-							hash := md5.New()
-							if _, err := io.Copy(hash, file); err == nil {
-								bMap.MD5 = hex.EncodeToString(hash.Sum(nil))
-								newBeatmaps = append(newBeatmaps, bMap)
-							}
-						}
+					if bMap := importBeatmap(osPathname, stat); bMap != nil {
+						newBeatmaps = append(newBeatmaps, bMap)
 					}
 				} else {
 					bMap := beatmap.NewBeatMap()
@@ -231,18 +215,50 @@ func LoadBeatmaps() []*beatmap.BeatMap {
 	log.Println("Loaded", len(allMaps), "total.")
 
 	result := make([]*beatmap.BeatMap, 0)
-	stars := make([]interface{}, 0)
 
 	for _, b := range allMaps {
 		if b.Mode == 0 {
-			if b.Stars < 0 {
-				stars = append(stars, b)
-			}
-
 			result = append(result, b)
 		}
 	}
 
+	updateStarRating(result)
+
+	return result
+}
+
+func importBeatmap(osPathname string, stat os.FileInfo) *beatmap.BeatMap {
+	file, err := os.Open(osPathname)
+	if err != nil {
+		return nil
+	}
+
+	defer file.Close()
+
+	if bMap := beatmap.ParseBeatMapFile(file); bMap != nil {
+		bMap.LastModified = stat.ModTime().UnixNano() / 1000000
+		bMap.TimeAdded = time.Now().UnixNano() / 1000000
+		log.Println("Importing:", bMap.File)
+
+		hash := md5.New()
+		if _, err := io.Copy(hash, file); err == nil {
+			bMap.MD5 = hex.EncodeToString(hash.Sum(nil))
+			return bMap
+		}
+	}
+
+	return nil
+}
+
+func updateStarRating(bMaps []*beatmap.BeatMap) {
+	stars := make([]interface{}, 0)
+
+	for _, b := range bMaps {
+		if b.Stars < 0 {
+			stars = append(stars, b)
+		}
+	}
+
 	if len(stars) > 0 {
 		log.Println("Updating star rating...")
 
@@ -297,8 +313,6 @@ func LoadBeatmaps() []*beatmap.BeatMap {
 
 		log.Println("Calculations finished")
 	}
-
-	return result
 }
 
 func UpdatePlayStats(beatmap *beatmap.BeatMap) {
@@ -568,3 +582,19 @@ func getLastModified() map[string]int64 {
 
 	return mod
 }
+
+func getLastModifiedDir(dir string) map[string]int64 {
+	res, _ := dbFile.Query("SELECT file, lastModified FROM beatmaps WHERE dir = ?", dir)
+
+	mod := make(map[string]int64)
+
+	for res.Next() {
+		var file string
+		var lastModified int64
+
+		res.Scan(&file, &lastModified)
+		mod[file] = lastModified
+	}
+
+	return mod
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF
+
+// startNotifier watches the Songs directory and every beatmap set directory inside it using inotify.
+// Paths of changed entries are sent to changes, the Songs directory itself is sent when the kernel queue overflows.
+func startNotifier(searchDir string, changes chan<- string) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		return err
+	}
+
+	watches := make(map[int32]string)
+
+	addWatch := func(path string) error {
+		wd, err := syscall.InotifyAddWatch(fd, path, inotifyMask)
+		if err != nil {
+			return err
+		}
+
+		watches[int32(wd)] = path
+
+		return nil
+	}
+
+	if err = addWatch(searchDir); err != nil {
+		syscall.Close(fd)
+		return err
+	}
+
+	files, _ := ioutil.ReadDir(searchDir)
+
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+
+		if err = addWatch(filepath.Join(searchDir, f.Name())); err != nil {
+			syscall.Close(fd)
+			return err
+		}
+	}
+
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+		for {
+			n, err := syscall.Read(fd, buf)
+			if err != nil {
+				if err == syscall.EINTR {
+					continue
+				}
+
+				log.Println("Songs directory watcher stopped:", err)
+				return
+			}
+
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+
+				nameStart := offset + syscall.SizeofInotifyEvent
+				offset = nameStart + int(event.Len)
+
+				if event.Mask&syscall.IN_Q_OVERFLOW > 0 {
+					changes <- searchDir
+					continue
+				}
+
+				dir, ok := watches[event.Wd]
+				if !ok {
+					continue
+				}
+
+				if event.Mask&syscall.IN_IGNORED > 0 {
+					delete(watches, event.Wd)
+					continue
+				}
+
+				path := dir
+
+				if event.Len > 0 {
+					name := buf[nameStart:offset]
+
+					for i, c := range name {
+						if c == 0 {
+							name = name[:i]
+							break
+						}
+					}
+
+					path = filepath.Join(dir, string(name))
+				}
+
+				if dir == searchDir && event.Mask&syscall.IN_ISDIR > 0 && event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) > 0 {
+					if err := addWatch(path); err != nil {
+						log.Println("Failed to watch", path+":", err)
+					}
+				}
+
+				changes <- path
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package database
+
+import "errors"
+
+func startNotifier(searchDir string, changes chan<- string) error {
+	return errors.New("not supported on this platform")
+}
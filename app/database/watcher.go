@@ -0,0 +1,309 @@
+package database
+
+import (
+	"github.com/wieku/danser-go/app/beatmap"
+	"github.com/wieku/danser-go/app/settings"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type EventType int
+
+const (
+	BeatmapAdded = EventType(iota)
+	BeatmapUpdated
+	BeatmapRemoved
+)
+
+func (t EventType) String() string {
+	switch t {
+	case BeatmapAdded:
+		return "added"
+	case BeatmapUpdated:
+		return "updated"
+	case BeatmapRemoved:
+		return "removed"
+	}
+
+	return "unknown"
+}
+
+// BeatmapEvent describes a single difficulty change found by the Songs directory watcher.
+// Beatmap is nil for BeatmapRemoved events.
+type BeatmapEvent struct {
+	Type    EventType
+	Dir     string
+	File    string
+	Beatmap *beatmap.BeatMap
+}
+
+// Changes are collected for this long before being processed, so a burst of writes from the editor or an unpacker is handled once.
+const watchDebounce = 500 * time.Millisecond
+
+var watchListeners = make([]func(event BeatmapEvent), 0)
+var watchMutex = &sync.Mutex{}
+
+// AddWatchListener registers a function called (from the watcher goroutine) for every beatmap change found after LoadBeatmaps.
+func AddWatchListener(function func(event BeatmapEvent)) {
+	watchMutex.Lock()
+	watchListeners = append(watchListeners, function)
+	watchMutex.Unlock()
+}
+
+// StartWatcher watches settings.General.OsuSongsDir for new .osz archives and added, changed or deleted .osu files.
+// inotify is used when available, otherwise the directory is polled every settings.General.SongsDirPollInterval seconds.
+func StartWatcher() {
+	searchDir, err := filepath.Abs(settings.General.OsuSongsDir)
+	if err != nil {
+		log.Println("Invalid song path given:", settings.General.OsuSongsDir)
+		return
+	}
+
+	changes := make(chan string, 1024)
+
+	if err = startNotifier(searchDir, changes); err != nil {
+		log.Println("Can't use native filesystem notifications, falling back to polling:", err)
+		go pollSongsDir(searchDir, changes)
+	} else {
+		log.Println("Watching", searchDir, "for changes")
+	}
+
+	go processChanges(searchDir, changes)
+}
+
+func processChanges(searchDir string, changes <-chan string) {
+	pending := make(map[string]bool)
+
+	var timer <-chan time.Time
+
+	for {
+		select {
+		case path := <-changes:
+			pending[path] = true
+
+			if timer == nil {
+				timer = time.After(watchDebounce)
+			}
+		case <-timer:
+			timer = nil
+
+			if len(pending) == 0 {
+				continue
+			}
+
+			pending = applyChanges(searchDir, pending)
+
+			if len(pending) > 0 {
+				timer = time.After(watchDebounce)
+			}
+		}
+	}
+}
+
+// applyChanges processes collected paths and returns the ones that have to be retried later, like archives that are still being written.
+func applyChanges(searchDir string, pending map[string]bool) (retry map[string]bool) {
+	retry = make(map[string]bool)
+	dirs := make(map[string]bool)
+
+	for path := range pending {
+		if path == searchDir {
+			for _, dir := range listSetDirectories(searchDir) {
+				dirs[dir] = true
+			}
+
+			continue
+		}
+
+		rel, err := filepath.Rel(searchDir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+
+		if len(parts) == 1 && strings.HasSuffix(parts[0], ".osz") {
+			if stat, err := os.Stat(path); err == nil {
+				if time.Since(stat.ModTime()) < watchDebounce {
+					retry[path] = true
+					continue
+				}
+
+				if result := ImportArchive(path); result.Dir != "" {
+					dirs[result.Dir] = true
+				}
+			}
+
+			continue
+		}
+
+		dirs[parts[0]] = true
+	}
+
+	var events []BeatmapEvent
+
+	for dir := range dirs {
+		events = append(events, syncDirectory(searchDir, dir)...)
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	var toUpdate []*beatmap.BeatMap
+
+	for _, e := range events {
+		if e.Beatmap != nil {
+			toUpdate = append(toUpdate, e.Beatmap)
+		}
+	}
+
+	updateBeatmaps(toUpdate)
+
+	var stars []*beatmap.BeatMap
+
+	for _, b := range toUpdate {
+		if b.Mode == 0 {
+			stars = append(stars, b)
+		}
+	}
+
+	updateStarRating(stars)
+
+	watchMutex.Lock()
+	listeners := watchListeners
+	watchMutex.Unlock()
+
+	for _, e := range events {
+		log.Println("Beatmap", e.Type.String()+":", e.Dir+"/"+e.File)
+
+		for _, f := range listeners {
+			f(e)
+		}
+	}
+
+	return
+}
+
+// syncDirectory compares .osu files in a beatmap set directory with the database, reimporting new or modified difficulties and removing deleted ones.
+// New beatmaps are returned in events and still need to be inserted into the database.
+func syncDirectory(searchDir, dir string) (events []BeatmapEvent) {
+	cached := getLastModifiedDir(dir)
+
+	files, _ := ioutil.ReadDir(filepath.Join(searchDir, dir))
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".osu") {
+			continue
+		}
+
+		cachedTime, exists := cached[f.Name()]
+		delete(cached, f.Name())
+
+		if exists && cachedTime == f.ModTime().UnixNano()/1000000 {
+			continue
+		}
+
+		eventType := BeatmapAdded
+
+		if exists {
+			removeBeatmap(dir, f.Name())
+			eventType = BeatmapUpdated
+		}
+
+		bMap := importBeatmap(filepath.Join(searchDir, dir, f.Name()), f)
+		if bMap == nil {
+			if exists {
+				events = append(events, BeatmapEvent{Type: BeatmapRemoved, Dir: dir, File: f.Name()})
+			}
+
+			continue
+		}
+
+		events = append(events, BeatmapEvent{Type: eventType, Dir: dir, File: f.Name(), Beatmap: bMap})
+	}
+
+	for file := range cached {
+		removeBeatmap(dir, file)
+		events = append(events, BeatmapEvent{Type: BeatmapRemoved, Dir: dir, File: file})
+	}
+
+	return
+}
+
+// listSetDirectories returns beatmap set directories present either on disk or in the database.
+func listSetDirectories(searchDir string) (dirs []string) {
+	known := make(map[string]bool)
+
+	files, _ := ioutil.ReadDir(searchDir)
+
+	for _, f := range files {
+		if f.IsDir() {
+			known[f.Name()] = true
+		}
+	}
+
+	for key := range getLastModified() {
+		known[key[:strings.LastIndex(key, "/")]] = true
+	}
+
+	for dir := range known {
+		dirs = append(dirs, dir)
+	}
+
+	return
+}
+
+// pollSongsDir is the fallback for platforms without native notifications.
+// It reports directories whose contents changed since the last pass.
+func pollSongsDir(searchDir string, changes chan<- string) {
+	snapshot := scanSongsDir(searchDir)
+
+	for {
+		time.Sleep(time.Duration(settings.General.SongsDirPollInterval * float64(time.Second)))
+
+		current := scanSongsDir(searchDir)
+
+		for path, modTime := range current {
+			if prev, ok := snapshot[path]; !ok || prev != modTime {
+				changes <- path
+			}
+		}
+
+		for path := range snapshot {
+			if _, ok := current[path]; !ok {
+				changes <- path
+			}
+		}
+
+		snapshot = current
+	}
+}
+
+func scanSongsDir(searchDir string) map[string]int64 {
+	result := make(map[string]int64)
+
+	files, _ := ioutil.ReadDir(searchDir)
+
+	for _, f := range files {
+		path := filepath.Join(searchDir, f.Name())
+
+		if strings.HasSuffix(f.Name(), ".osz") {
+			result[path] = f.ModTime().UnixNano()
+		} else if f.IsDir() {
+			subFiles, _ := ioutil.ReadDir(path)
+
+			for _, sf := range subFiles {
+				if strings.HasSuffix(sf.Name(), ".osu") {
+					result[filepath.Join(path, sf.Name())] = sf.ModTime().UnixNano()
+				}
+			}
+		}
+	}
+
+	return result
+}
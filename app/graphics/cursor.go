@@ -6,7 +6,7 @@ import (
 	"github.com/wieku/danser-go/app/bmath"
 	"github.com/wieku/danser-go/app/bmath/camera"
 	"github.com/wieku/danser-go/app/settings"
-	"github.com/wieku/danser-go/framework/assets"
+	"github.com/wieku/danser-go/app/skin"
 	"github.com/wieku/danser-go/framework/graphics/attribute"
 	"github.com/wieku/danser-go/framework/graphics/batch"
 	"github.com/wieku/danser-go/framework/graphics/blend"
@@ -31,26 +31,19 @@ var cursorSpaceFBOSprite *sprite.Sprite
 var fboBatch *batch.QuadBatch
 
 var Camera *camera.Camera
-var osuRect camera.Rectangle
 
 var useAdditive = false
 
 func initCursor() {
-	if settings.Cursor.TrailStyle < 1 || settings.Cursor.TrailStyle > 4 {
+	if settings.Cursor.TrailStyle < 1 || settings.Cursor.TrailStyle > legacyTrailStyle {
 		panic("Wrong cursor trail type")
 	}
 
-	vert, err := assets.GetString("assets/shaders/cursortrail.vsh")
-	if err != nil {
-		panic(err)
+	if settings.Cursor.TrailStyle == legacyTrailStyle {
+		initLegacyCursor()
 	}
 
-	frag, err := assets.GetString("assets/shaders/cursortrail.fsh")
-	if err != nil {
-		panic(err)
-	}
-
-	cursorShader = shader.NewRShader(shader.NewSource(vert, shader.Vertex), shader.NewSource(frag, shader.Fragment))
+	cursorShader = shader.LoadRShader("assets/shaders/cursortrail.vsh", "assets/shaders/cursortrail.fsh")
 
 	cursorFbo = buffer.NewFrame(int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight()), true, false)
 	region := cursorFbo.Texture().GetRegion()
@@ -62,11 +55,12 @@ func initCursor() {
 
 	fboBatch = batch.NewQuadBatchSize(1)
 	fboBatch.SetCamera(mgl32.Ortho(0, float32(settings.Graphics.GetWidth()), 0, float32(settings.Graphics.GetHeight()), -1, 1))
-
-	osuRect = Camera.GetWorldRect()
 }
 
-type Cursor struct {
+// CursorRenderer draws the trail of a CursorState.
+type CursorRenderer struct {
+	state *CursorState
+
 	Points        []vector.Vector2f
 	PointsC       []float64
 	removeCounter float64
@@ -75,20 +69,9 @@ type Cursor struct {
 
 	lastLeftState, lastRightState bool
 
-	LeftButton, RightButton bool
-	LeftKey, RightKey       bool
-	LeftMouse, RightMouse   bool
-
-	IsReplayFrame    bool // TODO: temporary hacky solution for spinners
-	IsPlayer         bool
-	LastFrameTime    int64 //
-	CurrentFrameTime int64 //
-	Position         vector.Vector2f
-	LastPos          vector.Vector2f
-	VaoPos           vector.Vector2f
-	RendPos          vector.Vector2f
-
-	Name string
+	LastPos vector.Vector2f
+	VaoPos  vector.Vector2f
+	RendPos vector.Vector2f
 
 	vertices  []float32
 	vaoSize   int
@@ -99,9 +82,17 @@ type Cursor struct {
 	hueBase   float64
 	vecSize   int
 	instances int
+
+	legacyParts    []legacyTrailPart
+	legacyDraw     []legacyTrailPart
+	legacyTime     float64
+	legacyDrawTime float64
+	legacyCounter  float64
+	rotation       float64
+	drawRotation   float64
 }
 
-func NewCursor() *Cursor {
+func NewCursorRenderer(state *CursorState) *CursorRenderer {
 	if cursorShader == nil {
 		initCursor()
 	}
@@ -143,56 +134,29 @@ func NewCursor() *Cursor {
 	vao.Attach(cursorShader)
 	vao.Unbind()
 
-	cursor := &Cursor{LastPos: vector.NewVec2f(100, 100), Position: vector.NewVec2f(100, 100), vao: vao, mutex: &sync.Mutex{}, RendPos: vector.NewVec2f(100, 100), vertices: make([]float32, points*3)}
+	cursor := &CursorRenderer{state: state, LastPos: state.Position, vao: vao, mutex: &sync.Mutex{}, RendPos: state.Position, vertices: make([]float32, points*3)}
 	cursor.scale = animation.NewGlider(1.0)
 	cursor.vecSize = 3
 
 	return cursor
 }
 
-func (cursor *Cursor) SetPos(pt vector.Vector2f) {
-	tmp := pt
-
-	if settings.Cursor.BounceOnEdges && settings.DIVIDES <= 2 {
-		for {
-			ok1, ok2 := false, false
-
-			if tmp.X < osuRect.MinX {
-				tmp.X = 2*osuRect.MinX - tmp.X
-			} else if tmp.X > osuRect.MaxX {
-				tmp.X = 2*osuRect.MaxX - tmp.X
-			} else {
-				ok1 = true
-			}
-
-			if tmp.Y < osuRect.MinY {
-				tmp.Y = 2*osuRect.MinY - tmp.Y
-			} else if tmp.Y > osuRect.MaxY {
-				tmp.Y = 2*osuRect.MaxY - tmp.Y
-			} else {
-				ok2 = true
-			}
-
-			if ok1 && ok2 {
-				break
-			}
-		}
-	}
-
-	cursor.Position = tmp
-}
-
-func (cursor *Cursor) SetScreenPos(pt vector.Vector2f) {
-	cursor.SetPos(Camera.Unproject(pt.Copy64()).Copy32())
+func (cursor *CursorRenderer) GetState() *CursorState {
+	return cursor.state
 }
 
-func (cursor *Cursor) Update(delta float64) {
+func (cursor *CursorRenderer) Update(delta float64) {
 	delta = math.Abs(delta)
 
-	leftState := cursor.LeftKey || cursor.LeftMouse
-	rightState := cursor.RightKey || cursor.RightMouse
+	leftState := cursor.state.LeftKey || cursor.state.LeftMouse
+	rightState := cursor.state.RightKey || cursor.state.RightMouse
+	expand := settings.Cursor.CursorExpand
+	if settings.Cursor.TrailStyle == legacyTrailStyle {
+		expand = skin.GetInfo().CursorExpand
+	}
+
 	if cursor.lastLeftState != leftState || cursor.lastRightState != rightState {
-		if (leftState || rightState) && settings.Cursor.CursorExpand {
+		if (leftState || rightState) && expand {
 			cursor.scale.AddEventS(cursor.scale.GetTime(), cursor.scale.GetTime()+100, 1.0, 1.3)
 		} else {
 			cursor.scale.AddEventS(cursor.scale.GetTime(), cursor.scale.GetTime()+100, cursor.scale.GetValue(), 1.0)
@@ -204,6 +168,11 @@ func (cursor *Cursor) Update(delta float64) {
 
 	cursor.scale.UpdateD(delta)
 
+	if settings.Cursor.TrailStyle == legacyTrailStyle {
+		cursor.updateLegacy(delta)
+		return
+	}
+
 	if settings.Cursor.TrailStyle == 3 {
 		cursor.hueBase += settings.Cursor.Style23Speed / 360.0 * delta
 		if cursor.hueBase > 1.0 {
@@ -215,7 +184,7 @@ func (cursor *Cursor) Update(delta float64) {
 
 	lengthAdjusted := int(float64(settings.Cursor.TrailMaxLength) * settings.Cursor.TrailDensity)
 
-	points := cursor.Position.Dst(cursor.LastPos)
+	points := cursor.state.Position.Dst(cursor.LastPos)
 	distance := float32(1.0 / settings.Cursor.TrailDensity)
 
 	dirtyLocal := false
@@ -223,7 +192,7 @@ func (cursor *Cursor) Update(delta float64) {
 	if int(points/distance) > 0 {
 		temp := cursor.LastPos
 		for i := distance; i < points; i += distance {
-			temp = cursor.Position.Sub(cursor.LastPos).Scl(i / points).Add(cursor.LastPos)
+			temp = cursor.state.Position.Sub(cursor.LastPos).Scl(i / points).Add(cursor.LastPos)
 			cursor.Points = append(cursor.Points, temp)
 			cursor.PointsC = append(cursor.PointsC, cursor.hueBase)
 
@@ -285,11 +254,11 @@ func (cursor *Cursor) Update(delta float64) {
 
 		cursor.vaoDirty = true
 	}
-	cursor.VaoPos = cursor.Position
+	cursor.VaoPos = cursor.state.Position
 	cursor.mutex.Unlock()
 }
 
-func (cursor *Cursor) UpdateRenderer() {
+func (cursor *CursorRenderer) UpdateRenderer() {
 	cursor.mutex.Lock()
 	if cursor.vaoDirty {
 		cursor.vao.Resize("points", cursor.maxCap)
@@ -329,19 +298,43 @@ func EndCursorRender() {
 	blend.Pop()
 }
 
-func (cursor *Cursor) Draw(scale float64, batch *batch.QuadBatch, color color2.Color) {
+func (cursor *CursorRenderer) Draw(scale float64, batch *batch.QuadBatch, color color2.Color) {
 	cursor.DrawM(scale, batch, color, color)
 }
 
-func (cursor *Cursor) DrawM(scale float64, batch *batch.QuadBatch, color color2.Color, colorGlow color2.Color) {
-	hueShift := color.GetHue()
-
+func (cursor *CursorRenderer) DrawM(scale float64, batch *batch.QuadBatch, color color2.Color, colorGlow color2.Color) {
 	if useAdditive {
 		cursorFbo.Bind()
 		gl.ClearColor(0.0, 0.0, 0.0, 0.0)
 		gl.Clear(gl.COLOR_BUFFER_BIT)
 	}
 
+	if settings.Cursor.TrailStyle == legacyTrailStyle {
+		cursor.drawLegacy(scale, batch, color)
+	} else {
+		cursor.drawTrail(scale, batch, color, colorGlow)
+	}
+
+	if useAdditive {
+		cursorFbo.Unbind()
+
+		fboBatch.Begin()
+
+		blend.Push()
+		blend.SetFunction(blend.SrcAlpha, blend.One)
+
+		cursorFBOSprite.Draw(0, fboBatch)
+		fboBatch.Flush()
+
+		blend.Pop()
+
+		fboBatch.End()
+	}
+}
+
+func (cursor *CursorRenderer) drawTrail(scale float64, batch *batch.QuadBatch, color color2.Color, colorGlow color2.Color) {
+	hueShift := color.GetHue()
+
 	siz := settings.Cursor.CursorSize * cursor.scale.GetValue()
 
 	if settings.Cursor.EnableCustomTrailGlowOffset {
@@ -413,7 +406,7 @@ func (cursor *Cursor) DrawM(scale float64, batch *batch.QuadBatch, color color2.
 
 	position := cursor.RendPos
 	if settings.PLAY {
-		position = cursor.Position
+		position = cursor.state.Position
 	}
 
 	batch.ResetTransform()
@@ -428,20 +421,4 @@ func (cursor *Cursor) DrawM(scale float64, batch *batch.QuadBatch, color color2.
 	batch.DrawUnit(*CursorTop)
 
 	batch.End()
-
-	if useAdditive {
-		cursorFbo.Unbind()
-
-		fboBatch.Begin()
-
-		blend.Push()
-		blend.SetFunction(blend.SrcAlpha, blend.One)
-
-		cursorFBOSprite.Draw(0, fboBatch)
-		fboBatch.Flush()
-
-		blend.Pop()
-
-		fboBatch.End()
-	}
 }
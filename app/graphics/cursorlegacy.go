@@ -0,0 +1,195 @@
+package graphics
+
+import (
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/app/skin"
+	"github.com/wieku/danser-go/framework/graphics/batch"
+	"github.com/wieku/danser-go/framework/graphics/texture"
+	color2 "github.com/wieku/danser-go/framework/math/color"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+)
+
+// TrailStyle drawing the cursor from the current skin
+const legacyTrailStyle = 5
+
+const (
+	// time between trail parts when there's no cursormiddle
+	legacyTrailInterval = 1000.0 / 60
+	legacyTrailFade     = 150.0
+	legacyMaxParts      = 1024
+
+	// one rotation of the cursor in ms
+	legacyRotationTime = 10000.0
+)
+
+var skinCursor *texture.TextureRegion
+var skinCursorTrail *texture.TextureRegion
+var skinCursorMiddle *texture.TextureRegion
+
+type legacyTrailPart struct {
+	position vector.Vector2f
+	time     float64
+	rotation float64
+}
+
+func initLegacyCursor() {
+	skinCursor = skin.GetTextureSource("cursor", skin.SKIN)
+	skinCursorTrail = skin.GetTextureSource("cursortrail", skin.SKIN)
+	skinCursorMiddle = skin.GetTextureSource("cursormiddle", skin.SKIN)
+
+	if skinCursorTrail == nil {
+		region := CursorTrail.GetRegion()
+		skinCursorTrail = &region
+	}
+}
+
+// ReloadCursorTextures fetches cursor textures again after the skin has changed.
+func ReloadCursorTextures() {
+	if settings.Cursor.TrailStyle == legacyTrailStyle {
+		initLegacyCursor()
+	}
+}
+
+// cursor textures are in osu!pixels at the default CursorSize
+func getLegacyScale() float64 {
+	return settings.Cursor.CursorSize / 18
+}
+
+func (cursor *CursorRenderer) updateLegacy(delta float64) {
+	info := skin.GetInfo()
+
+	cursor.legacyTime += delta
+
+	if info.CursorRotate {
+		cursor.rotation = math.Mod(cursor.legacyTime/legacyRotationTime, 1) * 2 * math.Pi
+	}
+
+	rotation := 0.0
+	if info.CursorTrailRotate {
+		rotation = cursor.rotation
+	}
+
+	addPart := func(position vector.Vector2f) {
+		cursor.legacyParts = append(cursor.legacyParts, legacyTrailPart{position, cursor.legacyTime, rotation})
+	}
+
+	if skinCursorMiddle != nil {
+		// continuous trail: parts are spaced evenly along the path
+		spacing := float32(math.Max(1, float64(skinCursorTrail.Width)/2.5*getLegacyScale()*settings.Cursor.TrailScale))
+
+		distance := cursor.state.Position.Dst(cursor.LastPos)
+
+		if distance >= spacing {
+			direction := cursor.state.Position.Sub(cursor.LastPos).Scl(1 / distance)
+
+			for d := spacing; d <= distance; d += spacing {
+				cursor.LastPos = cursor.LastPos.Add(direction.Scl(spacing))
+				addPart(cursor.LastPos)
+			}
+		}
+	} else {
+		cursor.legacyCounter += delta
+
+		if cursor.legacyCounter >= legacyTrailInterval {
+			cursor.legacyCounter = math.Mod(cursor.legacyCounter, legacyTrailInterval)
+
+			if cursor.state.Position != cursor.LastPos {
+				cursor.LastPos = cursor.state.Position
+				addPart(cursor.LastPos)
+			}
+		}
+	}
+
+	fade := legacyTrailFade / math.Max(settings.Cursor.TrailRemoveSpeed, 0.01)
+
+	removed := 0
+	for removed < len(cursor.legacyParts) && (cursor.legacyTime-cursor.legacyParts[removed].time >= fade || len(cursor.legacyParts)-removed > legacyMaxParts) {
+		removed++
+	}
+
+	cursor.legacyParts = cursor.legacyParts[removed:]
+
+	cursor.mutex.Lock()
+	cursor.legacyDraw = append(cursor.legacyDraw[:0], cursor.legacyParts...)
+	cursor.legacyDrawTime = cursor.legacyTime
+	cursor.drawRotation = cursor.rotation
+	cursor.VaoPos = cursor.state.Position
+	cursor.mutex.Unlock()
+}
+
+func (cursor *CursorRenderer) drawLegacy(scale float64, batch *batch.QuadBatch, color color2.Color) {
+	info := skin.GetInfo()
+
+	baseScale := getLegacyScale() * scale
+
+	position := cursor.RendPos
+	if settings.PLAY {
+		position = cursor.state.Position
+	}
+
+	batch.Begin()
+	batch.ResetTransform()
+	batch.SetSubScale(1, 1)
+
+	cursor.mutex.Lock()
+
+	rotation := cursor.drawRotation
+
+	fade := legacyTrailFade / math.Max(settings.Cursor.TrailRemoveSpeed, 0.01)
+	trailScale := baseScale * settings.Cursor.TrailScale
+
+	batch.SetScale(trailScale, trailScale)
+
+	for _, part := range cursor.legacyDraw {
+		alpha := 1 - (cursor.legacyDrawTime-part.time)/fade
+		if alpha <= 0 {
+			continue
+		}
+
+		batch.SetTranslation(part.position.Copy64())
+		batch.SetRotation(part.rotation)
+		batch.SetColor(float64(color.R), float64(color.G), float64(color.B), float64(color.A)*alpha)
+		batch.DrawTexture(*skinCursorTrail)
+	}
+
+	cursor.mutex.Unlock()
+
+	batch.SetColorM(color)
+
+	if skinCursor == nil {
+		// skin has no cursor, danser's own is drawn instead
+		siz := settings.Cursor.CursorSize * cursor.scale.GetValue() * scale
+
+		batch.SetRotation(0)
+		batch.SetTranslation(position.Copy64())
+		batch.SetScale(siz, siz)
+		batch.DrawUnit(*CursorTex)
+		batch.SetColor(1, 1, 1, math.Sqrt(float64(color.A)))
+		batch.DrawUnit(*CursorTop)
+	} else {
+		cursorScale := baseScale * cursor.scale.GetValue()
+
+		translation := position.Copy64()
+		if !info.CursorCentre {
+			// texture is anchored and rotated at its top-left corner
+			translation = translation.Add(vector.NewVec2d(float64(skinCursor.Width), float64(skinCursor.Height)).Scl(cursorScale / 2).Rotate(rotation))
+		}
+
+		batch.SetRotation(rotation)
+		batch.SetTranslation(translation)
+		batch.SetScale(cursorScale, cursorScale)
+		batch.DrawTexture(*skinCursor)
+	}
+
+	if skinCursorMiddle != nil {
+		batch.SetRotation(0)
+		batch.SetTranslation(position.Copy64())
+		batch.SetScale(baseScale, baseScale)
+		batch.SetColorM(color)
+		batch.DrawTexture(*skinCursorMiddle)
+	}
+
+	batch.ResetTransform()
+	batch.End()
+}
@@ -0,0 +1,72 @@
+package graphics
+
+import (
+	"github.com/wieku/danser-go/app/bmath/camera"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/math/vector"
+)
+
+// Playfield bounds used by BounceOnEdges, taken from Camera on first use
+var osuRect *camera.Rectangle
+
+// CursorState holds cursor's position and input, it's used by controllers, schedulers and rulesets.
+// It doesn't need an OpenGL context, trails are drawn by CursorRenderer.
+type CursorState struct {
+	LeftButton, RightButton bool
+	LeftKey, RightKey       bool
+	LeftMouse, RightMouse   bool
+
+	IsReplayFrame    bool // TODO: temporary hacky solution for spinners
+	IsPlayer         bool
+	LastFrameTime    int64 //
+	CurrentFrameTime int64 //
+	Position         vector.Vector2f
+
+	Name string
+}
+
+func NewCursorState() *CursorState {
+	return &CursorState{Position: vector.NewVec2f(100, 100)}
+}
+
+func (cursor *CursorState) SetPos(pt vector.Vector2f) {
+	tmp := pt
+
+	// Bounds come from the camera, so cursors can't bounce before it's set up
+	if settings.Cursor.BounceOnEdges && settings.DIVIDES <= 2 && Camera != nil {
+		if osuRect == nil {
+			rect := Camera.GetWorldRect()
+			osuRect = &rect
+		}
+
+		for {
+			ok1, ok2 := false, false
+
+			if tmp.X < osuRect.MinX {
+				tmp.X = 2*osuRect.MinX - tmp.X
+			} else if tmp.X > osuRect.MaxX {
+				tmp.X = 2*osuRect.MaxX - tmp.X
+			} else {
+				ok1 = true
+			}
+
+			if tmp.Y < osuRect.MinY {
+				tmp.Y = 2*osuRect.MinY - tmp.Y
+			} else if tmp.Y > osuRect.MaxY {
+				tmp.Y = 2*osuRect.MaxY - tmp.Y
+			} else {
+				ok2 = true
+			}
+
+			if ok1 && ok2 {
+				break
+			}
+		}
+	}
+
+	cursor.Position = tmp
+}
+
+func (cursor *CursorState) SetScreenPos(pt vector.Vector2f) {
+	cursor.SetPos(Camera.Unproject(pt.Copy64()).Copy32())
+}
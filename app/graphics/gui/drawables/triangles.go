@@ -1,6 +1,7 @@
 package drawables
 
 import (
+	"github.com/wieku/danser-go/app/bmath"
 	"github.com/wieku/danser-go/app/graphics"
 	"github.com/wieku/danser-go/app/settings"
 	"github.com/wieku/danser-go/framework/bass"
@@ -29,10 +30,11 @@ type Triangles struct {
 	fft          []float64
 	colorPalette []color2.Color
 	music        *bass.Track
+	random       *rand.Rand
 }
 
 func NewTriangles(colors []color2.Color) *Triangles {
-	visualiser := &Triangles{triangles: make([]*sprite.Sprite, 0), velocity: 100}
+	visualiser := &Triangles{triangles: make([]*sprite.Sprite, 0), velocity: 100, random: bmath.NewRandom(settings.SEED, "triangles")}
 	visualiser.colorPalette = colors
 
 	for i := 0; i < maxTriangles; i++ {
@@ -49,8 +51,8 @@ func (vis *Triangles) SetTrack(track *bass.Track) {
 }
 
 func (vis *Triangles) AddTriangle(onscreen bool) {
-	size := (minSize + rand.Float64()*(maxSize-minSize)) * settings.Graphics.GetHeightF() / 768
-	position := vector.NewVec2d((rand.Float64()-0.5)*settings.Graphics.GetWidthF(), settings.Graphics.GetHeightF()/2+size)
+	size := (minSize + vis.random.Float64()*(maxSize-minSize)) * settings.Graphics.GetHeightF() / 768
+	position := vector.NewVec2d((vis.random.Float64()-0.5)*settings.Graphics.GetWidthF(), settings.Graphics.GetHeightF()/2+size)
 
 	texture := graphics.Triangle
 	if settings.Playfield.Background.Triangles.Shadowed {
@@ -59,17 +61,17 @@ func (vis *Triangles) AddTriangle(onscreen bool) {
 
 	sprite := sprite.NewSpriteSingle(texture, size, position, vector.NewVec2d(0, 0))
 	if vis.colorPalette == nil || len(vis.colorPalette) == 0 {
-		sprite.SetColor(color2.NewRGB(rand.Float32(), rand.Float32(), rand.Float32()))
+		sprite.SetColor(color2.NewRGB(vis.random.Float32(), vis.random.Float32(), vis.random.Float32()))
 	} else {
-		col := vis.colorPalette[rand.Intn(len(vis.colorPalette))]
+		col := vis.colorPalette[vis.random.Intn(len(vis.colorPalette))]
 		sprite.SetColor(col)
 	}
 
-	sprite.SetVFlip(rand.Float64() >= 0.5)
+	sprite.SetVFlip(vis.random.Float64() >= 0.5)
 	sprite.SetScale(size / float64(graphics.Triangle.Height))
 	//sprite.SetAlpha(0.65) //0.5+rand.Float64()*0.5)
 	if onscreen {
-		sprite.SetPosition(vector.NewVec2d(sprite.GetPosition().X, -(rand.Float64()-0.5)*(settings.Graphics.GetHeightF()+size)))
+		sprite.SetPosition(vector.NewVec2d(sprite.GetPosition().X, -(vis.random.Float64()-0.5)*(settings.Graphics.GetHeightF()+size)))
 		//sprite.AddTransform(animation.NewSingleTransform(animation.MoveY, easing.OutQuad, -2000, -1000, position.Y, -(rand.Float64() - 0.5)*(settings.Graphics.GetHeightF()+size)), false)
 	}
 
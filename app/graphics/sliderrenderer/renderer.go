@@ -5,7 +5,6 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/wieku/danser-go/app/bmath"
 	"github.com/wieku/danser-go/app/settings"
-	"github.com/wieku/danser-go/framework/assets"
 	"github.com/wieku/danser-go/framework/graphics/attribute"
 	batch2 "github.com/wieku/danser-go/framework/graphics/batch"
 	"github.com/wieku/danser-go/framework/graphics/blend"
@@ -29,27 +28,9 @@ var batch *batch2.QuadBatch
 
 func InitRenderer() {
 
-	passSource, err := assets.GetString("assets/shaders/sliderpass.vsh")
+	sliderShader = shader.LoadRShader("assets/shaders/sliderpass.vsh")
 
-	if err != nil {
-		panic(err)
-	}
-
-	sliderShader = shader.NewRShader(shader.NewSource(string(passSource), shader.Vertex))
-
-	colorVSource, err := assets.GetString("assets/shaders/slidercolor.vsh")
-
-	if err != nil {
-		panic(err)
-	}
-
-	colorFSource, err := assets.GetString("assets/shaders/slidercolor.fsh")
-
-	if err != nil {
-		panic(err)
-	}
-
-	colorShader = shader.NewRShader(shader.NewSource(string(colorVSource), shader.Vertex), shader.NewSource(string(colorFSource), shader.Fragment))
+	colorShader = shader.LoadRShader("assets/shaders/slidercolor.vsh", "assets/shaders/slidercolor.fsh")
 
 	colorVAO = buffer.NewVertexArrayObject()
 
@@ -0,0 +1,219 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/wieku/danser-go/framework/bass"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// VolumePoint is the music volume at a given recording time in ms.
+type VolumePoint struct {
+	Time   float64
+	Volume float64
+}
+
+// Music describes how the music was played during a recording.
+type Music struct {
+	Path string
+
+	// Recording time in ms when the music started and the file position in seconds it started from
+	Start    float64
+	Position float64
+
+	Tempo, Pitch float64
+
+	// Volume changes over the recording, interpolated linearly between points
+	Volume []VolumePoint
+}
+
+func (music Music) volumeAt(time float64) float64 {
+	points := music.Volume
+
+	if len(points) == 0 {
+		return 1
+	}
+
+	index := sort.Search(len(points), func(i int) bool {
+		return points[i].Time > time
+	})
+
+	if index == 0 {
+		return points[0].Volume
+	} else if index == len(points) {
+		return points[len(points)-1].Volume
+	}
+
+	p1, p2 := points[index-1], points[index]
+
+	return p1.Volume + (p2.Volume-p1.Volume)*(time-p1.Time)/(p2.Time-p1.Time)
+}
+
+// mixAudio renders music and captured samples into interleaved stereo samples at bass.MixFrequency, length is in ms.
+// Music is skipped when Music.Path is empty.
+func mixAudio(music Music, events []bass.SampleEvent, length float64) ([]float32, error) {
+	mix := make([]float32, int(length/1000*bass.MixFrequency)*2)
+
+	if music.Path != "" {
+		data, err := bass.DecodeTrack(music.Path, music.Position, music.Tempo, music.Pitch)
+		if err != nil {
+			return nil, err
+		}
+
+		start := int(music.Start / 1000 * bass.MixFrequency)
+
+		for i := 0; i < len(data)/2 && start+i < len(mix)/2; i++ {
+			if start+i < 0 {
+				continue
+			}
+
+			volume := float32(music.volumeAt(float64(start+i) * 1000 / bass.MixFrequency))
+
+			mix[(start+i)*2] += data[i*2] * volume
+			mix[(start+i)*2+1] += data[i*2+1] * volume
+		}
+	}
+
+	decoded := make(map[*bass.Sample][]float32)
+
+	for _, event := range events {
+		data, ok := decoded[event.Sample]
+		if !ok {
+			data = event.Sample.Decode()
+			decoded[event.Sample] = data
+		}
+
+		mixSample(mix, data, event)
+	}
+
+	return mix, nil
+}
+
+func mixSample(mix, data []float32, event bass.SampleEvent) {
+	frames := len(data) / 2
+	if frames == 0 {
+		return
+	}
+
+	speed := event.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	// same as BASS: panning only attenuates the opposite channel
+	left := float32(event.Volume * math.Min(1, 1-event.Balance))
+	right := float32(event.Volume * math.Min(1, 1+event.Balance))
+
+	start := int(event.Time / 1000 * bass.MixFrequency)
+
+	end := start + int(math.Ceil(float64(frames)/speed))
+	if event.Loop {
+		end = int(event.End / 1000 * bass.MixFrequency)
+	}
+
+	position := event.Offset * bass.MixFrequency
+
+	for i := start; i < end && i < len(mix)/2; i, position = i+1, position+speed {
+		if i < 0 {
+			continue
+		}
+
+		index := int(position)
+		next := index + 1
+
+		if event.Loop {
+			index %= frames
+			next %= frames
+		} else if index >= frames {
+			break
+		} else if next >= frames {
+			next = index
+		}
+
+		t := float32(position - math.Floor(position))
+
+		mix[i*2] += (data[index*2] + (data[next*2]-data[index*2])*t) * left
+		mix[i*2+1] += (data[index*2+1] + (data[next*2+1]-data[index*2+1])*t) * right
+	}
+}
+
+// SaveAudio mixes music and captured samples and saves them to a WAV file, length of the audio is given in ms.
+func SaveAudio(path string, music Music, events []bass.SampleEvent, length float64) error {
+	mix, err := mixAudio(music, events, length)
+	if err != nil {
+		return err
+	}
+
+	return writeWAV(path, mix)
+}
+
+// writeWAV saves stereo float samples as a 16-bit PCM WAV file, samples outside [-1, 1] are clipped.
+func writeWAV(path string, data []float32) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+
+	if err = encodeWAV(writer, data); err == nil {
+		err = writer.Flush()
+	}
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func encodeWAV(writer io.Writer, data []float32) error {
+	const channels = 2
+	const bytesPerSample = 2
+
+	dataSize := uint32(len(data) * bytesPerSample)
+
+	header := []interface{}{
+		[4]byte{'R', 'I', 'F', 'F'},
+		36 + dataSize,
+		[4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '},
+		uint32(16),
+		uint16(1), // PCM
+		uint16(channels),
+		uint32(bass.MixFrequency),
+		uint32(bass.MixFrequency * channels * bytesPerSample),
+		uint16(channels * bytesPerSample),
+		uint16(bytesPerSample * 8),
+		[4]byte{'d', 'a', 't', 'a'},
+		dataSize,
+	}
+
+	for _, field := range header {
+		if err := binary.Write(writer, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+
+	buffer := make([]byte, 4096)
+
+	for i := 0; i < len(data); i += len(buffer) / 2 {
+		count := 0
+
+		for j := i; j < len(data) && count < len(buffer); j++ {
+			value := int16(math.Max(-1, math.Min(1, float64(data[j]))) * 32767)
+			binary.LittleEndian.PutUint16(buffer[count:], uint16(value))
+			count += 2
+		}
+
+		if _, err := writer.Write(buffer[:count]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/wieku/danser-go/framework/bass"
+	"math"
+	"testing"
+)
+
+func TestEncodeWAV(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := encodeWAV(&buf, []float32{0, 0.5, -0.5, 1, -1, 1.5, -2, float32(math.Inf(1))}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.Bytes()
+
+	if len(out) != 44+8*2 {
+		t.Fatalf("got %d bytes, expected %d", len(out), 44+8*2)
+	}
+
+	le := binary.LittleEndian
+
+	header := []struct {
+		offset int
+		name   string
+		value  interface{}
+	}{
+		{0, "chunk id", "RIFF"},
+		{4, "chunk size", uint32(36 + 16)},
+		{8, "format", "WAVE"},
+		{12, "subchunk id", "fmt "},
+		{16, "subchunk size", uint32(16)},
+		{20, "audio format", uint16(1)},
+		{22, "channels", uint16(2)},
+		{24, "sample rate", uint32(bass.MixFrequency)},
+		{28, "byte rate", uint32(bass.MixFrequency * 4)},
+		{32, "block align", uint16(4)},
+		{34, "bits per sample", uint16(16)},
+		{36, "data id", "data"},
+		{40, "data size", uint32(16)},
+	}
+
+	for _, field := range header {
+		var actual interface{}
+
+		switch field.value.(type) {
+		case string:
+			actual = string(out[field.offset : field.offset+4])
+		case uint32:
+			actual = le.Uint32(out[field.offset:])
+		case uint16:
+			actual = le.Uint16(out[field.offset:])
+		}
+
+		if actual != field.value {
+			t.Errorf("%s at %d is %v, expected %v", field.name, field.offset, actual, field.value)
+		}
+	}
+
+	// samples outside [-1, 1] are clipped
+	expected := []int16{0, 16383, -16383, 32767, -32767, 32767, -32767, 32767}
+
+	for i, e := range expected {
+		if actual := int16(le.Uint16(out[44+i*2:])); actual != e {
+			t.Errorf("sample %d is %d, expected %d", i, actual, e)
+		}
+	}
+}
+
+func TestMixSample(t *testing.T) {
+	data := []float32{1, 1, 0.5, 0.5, 0.25, 0.25}
+
+	tests := []struct {
+		name     string
+		event    bass.SampleEvent
+		expected []float32
+	}{
+		{
+			name:     "plain",
+			event:    bass.SampleEvent{Volume: 1},
+			expected: []float32{1, 1, 0.5, 0.5, 0.25, 0.25, 0, 0},
+		},
+		{
+			name:     "delayed",
+			event:    bass.SampleEvent{Time: 1000 / float64(bass.MixFrequency), Volume: 0.5},
+			expected: []float32{0, 0, 0.5, 0.5, 0.25, 0.25, 0.125, 0.125},
+		},
+		{
+			name:     "panned left",
+			event:    bass.SampleEvent{Volume: 1, Balance: -0.5},
+			expected: []float32{1, 0.5, 0.5, 0.25, 0.25, 0.125, 0, 0},
+		},
+		{
+			name:     "double speed",
+			event:    bass.SampleEvent{Volume: 1, Speed: 2},
+			expected: []float32{1, 1, 0.25, 0.25, 0, 0, 0, 0},
+		},
+		{
+			name:     "starts before the mix",
+			event:    bass.SampleEvent{Time: -1000 / float64(bass.MixFrequency), Volume: 1},
+			expected: []float32{0.5, 0.5, 0.25, 0.25, 0, 0, 0, 0},
+		},
+		{
+			name:     "loop",
+			event:    bass.SampleEvent{Volume: 1, Loop: true, End: 4000 / float64(bass.MixFrequency)},
+			expected: []float32{1, 1, 0.5, 0.5, 0.25, 0.25, 1, 1},
+		},
+	}
+
+	for _, test := range tests {
+		mix := make([]float32, 8)
+		mixSample(mix, data, test.event)
+
+		for i := range mix {
+			if math.Abs(float64(mix[i]-test.expected[i])) > 1e-6 {
+				t.Errorf("%s: got %v, expected %v", test.name, mix, test.expected)
+				break
+			}
+		}
+	}
+}
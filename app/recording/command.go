@@ -0,0 +1,49 @@
+package recording
+
+import (
+	"errors"
+	"strings"
+)
+
+// splitCommand splits a command line into arguments on whitespace. Parts enclosed in double or single quotes
+// are kept together, so paths like "C:\Program Files\ffmpeg\bin\ffmpeg.exe" work. Backslashes have no special meaning.
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+
+	inArg := false
+	quote := rune(0)
+
+	for _, c := range command {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote in command: " + command)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
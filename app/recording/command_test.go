@@ -0,0 +1,44 @@
+package recording
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"ffmpeg -i {video}", []string{"ffmpeg", "-i", "{video}"}},
+		{"  ffmpeg \t -y\n-i  x ", []string{"ffmpeg", "-y", "-i", "x"}},
+		{`"C:\Program Files\ffmpeg\bin\ffmpeg.exe" -y`, []string{`C:\Program Files\ffmpeg\bin\ffmpeg.exe`, "-y"}},
+		{`ffmpeg -metadata 'title=a "quoted" name'`, []string{"ffmpeg", "-metadata", `title=a "quoted" name`}},
+		{`ffmpeg -vf "scale=1280:720" out.mp4`, []string{"ffmpeg", "-vf", "scale=1280:720", "out.mp4"}},
+		{`ffmpeg pre"fix suf"fix`, []string{"ffmpeg", "prefix suffix"}},
+		{`ffmpeg "" -y`, []string{"ffmpeg", "", "-y"}},
+		{`a\b "c\"`, []string{`a\b`, `c\`}},
+	}
+
+	for _, test := range tests {
+		args, err := splitCommand(test.command)
+		if err != nil {
+			t.Errorf("splitCommand(%q) failed: %v", test.command, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(args, test.args) {
+			t.Errorf("splitCommand(%q) = %q, expected %q", test.command, args, test.args)
+		}
+	}
+}
+
+func TestSplitCommandUnterminatedQuote(t *testing.T) {
+	for _, command := range []string{`ffmpeg "-y`, `ffmpeg '-y`, `"`, `ffmpeg "a" 'b`} {
+		if args, err := splitCommand(command); err == nil {
+			t.Errorf("splitCommand(%q) = %q, expected an error", command, args)
+		}
+	}
+}
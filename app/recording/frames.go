@@ -0,0 +1,266 @@
+package recording
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/wieku/danser-go/app/settings"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// frameWriter consumes top-down rgb24 frames.
+type frameWriter interface {
+	WriteFrame(rgb []uint8) error
+	Close() error
+
+	// Abort releases the output without finishing it
+	Abort()
+}
+
+func newFrameWriter(output string, width, height int) (frameWriter, error) {
+	switch strings.ToLower(settings.Recording.Output) {
+	case "command":
+		return newCommandWriter(output, width, height)
+	case "png":
+		return newPNGWriter(output, width, height)
+	case "y4m":
+		return newY4MWriter(output, width, height)
+	}
+
+	return nil, fmt.Errorf("unknown recording output: %s", settings.Recording.Output)
+}
+
+// commandWriter streams raw frames to the standard input of the encoder process.
+type commandWriter struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	log   *os.File
+}
+
+func newCommandWriter(output string, width, height int) (*commandWriter, error) {
+	args, err := splitCommand(settings.Recording.EncoderCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("Recording.EncoderCommand is empty")
+	}
+
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, "{width}", strconv.Itoa(width))
+		arg = strings.ReplaceAll(arg, "{height}", strconv.Itoa(height))
+		arg = strings.ReplaceAll(arg, "{fps}", strconv.FormatFloat(settings.Recording.FPS, 'f', -1, 64))
+		args[i] = strings.ReplaceAll(arg, "{output}", output)
+	}
+
+	writer := &commandWriter{cmd: exec.Command(args[0], args[1:]...)}
+
+	if writer.log, err = os.Create("encoder.log"); err != nil {
+		return nil, err
+	}
+
+	writer.cmd.Stdout = writer.log
+	writer.cmd.Stderr = writer.log
+
+	if writer.stdin, err = writer.cmd.StdinPipe(); err != nil {
+		writer.log.Close()
+		return nil, err
+	}
+
+	if err = writer.cmd.Start(); err != nil {
+		writer.log.Close()
+		return nil, fmt.Errorf("failed to start the encoder: %w", err)
+	}
+
+	return writer, nil
+}
+
+func (writer *commandWriter) WriteFrame(rgb []uint8) error {
+	if _, err := writer.stdin.Write(rgb); err != nil {
+		return fmt.Errorf("encoder stopped accepting frames, check encoder.log: %w", err)
+	}
+
+	return nil
+}
+
+func (writer *commandWriter) Close() error {
+	defer writer.log.Close()
+
+	writer.stdin.Close()
+
+	if err := writer.cmd.Wait(); err != nil {
+		return fmt.Errorf("encoder failed, check encoder.log: %w", err)
+	}
+
+	return nil
+}
+
+func (writer *commandWriter) Abort() {
+	defer writer.log.Close()
+
+	writer.cmd.Process.Kill()
+	writer.stdin.Close()
+	writer.cmd.Wait()
+}
+
+// pngWriter saves numbered images in the output directory, encoding runs on all cores.
+type pngWriter struct {
+	dir           string
+	width, height int
+	frame         int
+
+	workers chan struct{}
+	wait    sync.WaitGroup
+
+	errMutex sync.Mutex
+	err      error
+}
+
+func newPNGWriter(output string, width, height int) (*pngWriter, error) {
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return nil, err
+	}
+
+	return &pngWriter{
+		dir:     output,
+		width:   width,
+		height:  height,
+		workers: make(chan struct{}, runtime.NumCPU()),
+	}, nil
+}
+
+func (writer *pngWriter) WriteFrame(rgb []uint8) error {
+	writer.errMutex.Lock()
+	err := writer.err
+	writer.errMutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, writer.width, writer.height))
+
+	for i, j := 0, 0; i < len(rgb); i, j = i+3, j+4 {
+		img.Pix[j] = rgb[i]
+		img.Pix[j+1] = rgb[i+1]
+		img.Pix[j+2] = rgb[i+2]
+		img.Pix[j+3] = 0xFF
+	}
+
+	path := filepath.Join(writer.dir, fmt.Sprintf("frame_%06d.png", writer.frame))
+	writer.frame++
+
+	writer.workers <- struct{}{}
+	writer.wait.Add(1)
+
+	go func() {
+		defer func() {
+			<-writer.workers
+			writer.wait.Done()
+		}()
+
+		if err := savePNG(path, img); err != nil {
+			writer.errMutex.Lock()
+			writer.err = err
+			writer.errMutex.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+func (writer *pngWriter) Close() error {
+	writer.wait.Wait()
+	return writer.err
+}
+
+func (writer *pngWriter) Abort() {
+	writer.wait.Wait()
+}
+
+func savePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// y4mWriter writes an uncompressed YUV4MPEG2 stream with full resolution chroma.
+type y4mWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+	yuv    []uint8
+}
+
+func newY4MWriter(output string, width, height int) (*y4mWriter, error) {
+	file, err := os.Create(output)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &y4mWriter{
+		file:   file,
+		writer: bufio.NewWriterSize(file, width*height*3+6),
+		yuv:    make([]uint8, width*height*3),
+	}
+
+	fps := int(settings.Recording.FPS * 1000)
+
+	if _, err = fmt.Fprintf(writer.writer, "YUV4MPEG2 W%d H%d F%d:1000 Ip A1:1 C444\n", width, height, fps); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (writer *y4mWriter) WriteFrame(rgb []uint8) error {
+	plane := len(rgb) / 3
+
+	// BT.601 limited range
+	for i := 0; i < plane; i++ {
+		r, g, b := float64(rgb[i*3]), float64(rgb[i*3+1]), float64(rgb[i*3+2])
+
+		writer.yuv[i] = uint8(16 + 0.257*r + 0.504*g + 0.098*b + 0.5)
+		writer.yuv[plane+i] = uint8(128 - 0.148*r - 0.291*g + 0.439*b + 0.5)
+		writer.yuv[2*plane+i] = uint8(128 + 0.439*r - 0.368*g - 0.071*b + 0.5)
+	}
+
+	if _, err := writer.writer.WriteString("FRAME\n"); err != nil {
+		return err
+	}
+
+	_, err := writer.writer.Write(writer.yuv)
+
+	return err
+}
+
+func (writer *y4mWriter) Close() error {
+	if err := writer.writer.Flush(); err != nil {
+		writer.file.Close()
+		return err
+	}
+
+	return writer.file.Close()
+}
+
+func (writer *y4mWriter) Abort() {
+	writer.file.Close()
+}
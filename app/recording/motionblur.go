@@ -0,0 +1,40 @@
+package recording
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// motionBlurWeights returns normalized weights of subframes, from the oldest to the newest.
+func motionBlurWeights(count int, weighting string) ([]float64, error) {
+	weights := make([]float64, count)
+
+	var sum float64
+
+	for i := range weights {
+		// position of the subframe within the frame, 0 is the oldest
+		x := (float64(i) + 0.5) / float64(count)
+
+		switch strings.ToLower(weighting) {
+		case "flat":
+			weights[i] = 1
+		case "gaussian":
+			// sigma is a quarter of the frame
+			d := (x - 0.5) / 0.25
+			weights[i] = math.Exp(-0.5 * d * d)
+		case "front":
+			weights[i] = x * x
+		default:
+			return nil, fmt.Errorf("unknown motion blur weighting: %s", weighting)
+		}
+
+		sum += weights[i]
+	}
+
+	for i := range weights {
+		weights[i] /= sum
+	}
+
+	return weights, nil
+}
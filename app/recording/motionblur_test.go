@@ -0,0 +1,64 @@
+package recording
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMotionBlurWeights(t *testing.T) {
+	for _, weighting := range []string{"flat", "gaussian", "front", "Gaussian"} {
+		for _, count := range []int{1, 2, 3, 8, 16, 31} {
+			weights, err := motionBlurWeights(count, weighting)
+			if err != nil {
+				t.Fatalf("%s, %d subframes: %v", weighting, count, err)
+			}
+
+			if len(weights) != count {
+				t.Fatalf("%s, %d subframes: got %d weights", weighting, count, len(weights))
+			}
+
+			sum := 0.0
+
+			for i, w := range weights {
+				if w <= 0 {
+					t.Errorf("%s, %d subframes: weight %d is %f, expected a positive value", weighting, count, i, w)
+				}
+
+				sum += w
+			}
+
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("%s, %d subframes: weights sum to %f, expected 1", weighting, count, sum)
+			}
+		}
+	}
+}
+
+func TestMotionBlurWeightsShape(t *testing.T) {
+	flat, _ := motionBlurWeights(4, "flat")
+	for i, w := range flat {
+		if math.Abs(w-0.25) > 1e-9 {
+			t.Errorf("flat weight %d is %f, expected 0.25", i, w)
+		}
+	}
+
+	front, _ := motionBlurWeights(4, "front")
+	for i := 1; i < len(front); i++ {
+		if front[i] <= front[i-1] {
+			t.Errorf("front weights should grow towards the newest subframe: %v", front)
+		}
+	}
+
+	gaussian, _ := motionBlurWeights(5, "gaussian")
+	for i := 0; i < 2; i++ {
+		if math.Abs(gaussian[i]-gaussian[4-i]) > 1e-9 || gaussian[i] >= gaussian[i+1] {
+			t.Errorf("gaussian weights should be symmetric and peak in the middle: %v", gaussian)
+		}
+	}
+}
+
+func TestMotionBlurWeightsUnknown(t *testing.T) {
+	if _, err := motionBlurWeights(4, "linear"); err == nil {
+		t.Error("expected an error for unknown weighting")
+	}
+}
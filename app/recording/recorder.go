@@ -0,0 +1,208 @@
+package recording
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/bass"
+	"github.com/wieku/danser-go/framework/graphics/buffer"
+	"github.com/wieku/danser-go/framework/graphics/effects"
+	"github.com/wieku/danser-go/framework/graphics/texture"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Recorder captures rendered frames into a video output and hitsounds for the audio mixdown.
+type Recorder struct {
+	output        string
+	width, height int
+
+	fbo    *buffer.Framebuffer
+	pixels []uint8
+	rgb    []uint8
+
+	frames   frameWriter
+	frame    int64
+	finished bool
+
+	blur     *effects.AccumulationEffect
+	weights  []float64
+	subframe int
+}
+
+// NewRecorder prepares frame output and starts capturing samples, it has to be created on the GL thread.
+func NewRecorder(output string, width, height int) (*Recorder, error) {
+	if settings.Recording.FPS <= 0 {
+		return nil, fmt.Errorf("Recording.FPS has to be greater than 0, got: %v", settings.Recording.FPS)
+	}
+
+	frames, err := newFrameWriter(output, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := &Recorder{
+		output: output,
+		width:  width,
+		height: height,
+		pixels: make([]uint8, width*height*4),
+		rgb:    make([]uint8, width*height*3),
+		frames: frames,
+	}
+
+	if settings.Graphics.MSAA > 0 {
+		recorder.fbo = buffer.NewFrameMultisample(width, height, true, false)
+	} else {
+		recorder.fbo = buffer.NewFrame(width, height, true, false)
+	}
+
+	if blur := settings.Playfield.MotionBlur; blur.Enabled && blur.Subframes > 1 {
+		if recorder.weights, err = motionBlurWeights(blur.Subframes, blur.Weighting); err != nil {
+			frames.Close()
+			return nil, err
+		}
+
+		recorder.blur = effects.NewAccumulationEffect(width, height)
+	}
+
+	bass.StartCapture()
+
+	log.Println("Recording to", output)
+
+	return recorder, nil
+}
+
+// GetSubframes returns how many times every frame has to be drawn, each time with Begin and End.
+// Subframes should be evenly spaced in time, they are blended into one frame when motion blur is enabled.
+func (recorder *Recorder) GetSubframes() int {
+	if recorder.blur == nil {
+		return 1
+	}
+
+	return len(recorder.weights)
+}
+
+// Begin redirects drawing to the recorded frame.
+func (recorder *Recorder) Begin() {
+	if recorder.blur != nil && recorder.subframe == 0 {
+		recorder.blur.Clear()
+	}
+
+	recorder.fbo.Bind()
+
+	gl.ClearColor(0, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+}
+
+// End finishes the frame and passes it to the output. With motion blur, the frame is written after its last subframe.
+func (recorder *Recorder) End() error {
+	recorder.fbo.Unbind()
+
+	if recorder.blur != nil {
+		recorder.blur.Add(recorder.fbo.Texture(), recorder.weights[recorder.subframe])
+
+		recorder.subframe++
+		if recorder.subframe < len(recorder.weights) {
+			return nil
+		}
+
+		recorder.subframe = 0
+
+		recorder.fbo.Bind()
+		recorder.blur.Render()
+		recorder.fbo.Unbind()
+	}
+
+	recorder.fbo.ReadPixels(recorder.pixels)
+
+	rowRGBA := recorder.width * 4
+	rowRGB := recorder.width * 3
+
+	// GL rows start at the bottom
+	for y := 0; y < recorder.height; y++ {
+		src := recorder.pixels[(recorder.height-1-y)*rowRGBA:]
+		dst := recorder.rgb[y*rowRGB:]
+
+		for x := 0; x < recorder.width; x++ {
+			dst[x*3] = src[x*4]
+			dst[x*3+1] = src[x*4+1]
+			dst[x*3+2] = src[x*4+2]
+		}
+	}
+
+	recorder.frame++
+
+	return recorder.frames.WriteFrame(recorder.rgb)
+}
+
+// Texture returns the last recorded frame, so it can be shown as a preview.
+func (recorder *Recorder) Texture() texture.Texture {
+	return recorder.fbo.Texture()
+}
+
+// GetFrame returns the number of recorded frames.
+func (recorder *Recorder) GetFrame() int64 {
+	return recorder.frame
+}
+
+// Abort stops capturing and kills the encoder without finishing the output. It does nothing if Finish was already called.
+func (recorder *Recorder) Abort() {
+	if recorder.finished {
+		return
+	}
+
+	recorder.finished = true
+
+	bass.StopCapture()
+	recorder.frames.Abort()
+
+	log.Println("Recording aborted after", recorder.frame, "frames")
+}
+
+// Finish closes the video output and writes music and captured hitsounds to a WAV file next to it.
+func (recorder *Recorder) Finish(music Music) error {
+	recorder.finished = true
+
+	events := bass.StopCapture()
+
+	if err := recorder.frames.Close(); err != nil {
+		return err
+	}
+
+	log.Println("Recorded", recorder.frame, "frames, mixing audio...")
+
+	name := strings.TrimSuffix(recorder.output, filepath.Ext(recorder.output))
+	audioPath := name + ".wav"
+
+	if err := SaveAudio(audioPath, music, events, float64(recorder.frame)*1000/settings.Recording.FPS); err != nil {
+		return err
+	}
+
+	log.Println("Audio saved to", audioPath)
+
+	args, err := splitCommand(settings.Recording.MuxCommand)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, "{video}", recorder.output)
+		arg = strings.ReplaceAll(arg, "{audio}", audioPath)
+		args[i] = strings.ReplaceAll(arg, "{name}", name)
+	}
+
+	log.Println("Running:", strings.Join(args, " "))
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
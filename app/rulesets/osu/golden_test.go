@@ -0,0 +1,104 @@
+package osu
+
+import (
+	"github.com/Mempler/rplpa"
+	"github.com/wieku/danser-go/app/beatmap/difficulty"
+	"github.com/wieku/danser-go/app/graphics"
+	"github.com/wieku/danser-go/app/testutil"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"io/ioutil"
+	"testing"
+)
+
+// Judgments of a bundled replay are recorded together with an idle cursor that misses everything.
+// golden.osr was exported from a humanized spline play (seed 7, UR 260) of golden.osu, so it has 100s, 50s and missed ticks.
+// Two cursors are used because the ruleset animates objects only when there's a single player.
+func TestGoldenReplay(t *testing.T) {
+	bMap := testutil.LoadBeatMap(t, "golden", "golden.osu")
+
+	data, err := ioutil.ReadFile(testutil.FixturePath("replays", "golden.osr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := rplpa.ParseReplay(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frames []*rplpa.ReplayData
+
+	for _, frame := range replay.ReplayData {
+		if frame.Time != -12345 {
+			frames = append(frames, frame)
+		}
+	}
+
+	player, idle := graphics.NewCursorState(), graphics.NewCursorState()
+	cursors := []*graphics.CursorState{player, idle}
+
+	ruleset := NewOsuRuleset(bMap, cursors, []difficulty.Modifier{difficulty.None, difficulty.None})
+
+	golden := &testutil.Golden{}
+
+	ruleset.SetListener(func(cursor *graphics.CursorState, time int64, number int64, position vector.Vector2d, result HitResult, comboResult ComboResult, pp float64, score int64) {
+		name := "player"
+		if cursor == idle {
+			name = "idle"
+		}
+
+		golden.Printf("%d %s object=%d result=%d combo=%d score=%d pp=%.2f", time, name, number, result, comboResult, score, pp)
+	})
+
+	replayTime := int64(0)
+	index := 0
+
+	for time := int64(-200); time <= 16000; time++ {
+		for index < len(frames) && replayTime+frames[index].Time <= time {
+			frame := frames[index]
+			replayTime += frame.Time
+
+			player.SetPos(vector.NewVec2f(frame.MosueX, frame.MouseY))
+
+			player.LastFrameTime = player.CurrentFrameTime
+			player.CurrentFrameTime = replayTime
+			player.IsReplayFrame = true
+
+			player.LeftKey = frame.KeyPressed.LeftClick && frame.KeyPressed.Key1
+			player.RightKey = frame.KeyPressed.RightClick && frame.KeyPressed.Key2
+			player.LeftMouse = frame.KeyPressed.LeftClick && !frame.KeyPressed.Key1
+			player.RightMouse = frame.KeyPressed.RightClick && !frame.KeyPressed.Key2
+			player.LeftButton = frame.KeyPressed.LeftClick
+			player.RightButton = frame.KeyPressed.RightClick
+
+			ruleset.UpdateClickFor(player, replayTime)
+			ruleset.UpdateNormalFor(player, replayTime)
+			ruleset.UpdatePostFor(player, replayTime)
+
+			index++
+		}
+
+		player.IsReplayFrame = false
+
+		idle.IsReplayFrame = time%16 == 0
+		if idle.IsReplayFrame {
+			idle.LastFrameTime = idle.CurrentFrameTime
+			idle.CurrentFrameTime = time
+		}
+
+		ruleset.UpdateClickFor(idle, time)
+		ruleset.UpdateNormalFor(idle, time)
+		ruleset.UpdatePostFor(idle, time)
+
+		ruleset.Update(time)
+	}
+
+	for i, cursor := range cursors {
+		accuracy, combo, score, grade := ruleset.GetResults(cursor)
+
+		golden.Printf("cursor %d accuracy=%.4f combo=%d score=%d grade=%d 300=%d 100=%d 50=%d miss=%d", i, accuracy, combo, score, grade,
+			ruleset.GetHitCount(cursor, Hit300), ruleset.GetHitCount(cursor, Hit100), ruleset.GetHitCount(cursor, Hit50), ruleset.GetHitCount(cursor, Miss))
+	}
+
+	golden.Check(t, "replay")
+}
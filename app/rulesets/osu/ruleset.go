@@ -73,8 +73,15 @@ type HitObject interface {
 	GetNumber() int64
 }
 
+// StrainPoint holds aim and speed strain of the object starting at Time.
+type StrainPoint struct {
+	Time  float64
+	Aim   float64
+	Speed float64
+}
+
 type difficultyPlayer struct {
-	cursor          *graphics.Cursor
+	cursor          *graphics.CursorState
 	diff            *difficulty.Difficulty
 	DoubleClick     bool
 	alreadyStolen   bool
@@ -105,28 +112,33 @@ type subSet struct {
 	currentKatu   int
 	currentBad    int
 	hp            *HealthProcessor
+	errorCount    int64
+	errorSum      float64
+	errorSumSq    float64
 }
 
 type OsuRuleSet struct {
 	beatMap         *beatmap.BeatMap
-	cursors         map[*graphics.Cursor]*subSet
+	cursors         map[*graphics.CursorState]*subSet
 	scoreMultiplier float64
 
 	ended bool
 
 	oppaiMaps []*oppai.Map
 	oppDiffs  map[difficulty.Modifier][]oppai.DiffCalc
+	strains   map[difficulty.Modifier][]StrainPoint
 
 	queue       []HitObject
 	processed   []HitObject
-	listener    func(cursor *graphics.Cursor, time int64, number int64, position vector.Vector2d, result HitResult, comboResult ComboResult, pp float64, score int64)
+	listener    func(cursor *graphics.CursorState, time int64, number int64, position vector.Vector2d, result HitResult, comboResult ComboResult, pp float64, score int64)
 	endlistener func(time int64, number int64)
 }
 
-func NewOsuRuleset(beatMap *beatmap.BeatMap, cursors []*graphics.Cursor, mods []difficulty.Modifier) *OsuRuleSet {
+func NewOsuRuleset(beatMap *beatmap.BeatMap, cursors []*graphics.CursorState, mods []difficulty.Modifier) *OsuRuleSet {
 	ruleset := new(OsuRuleSet)
 	ruleset.beatMap = beatMap
 	ruleset.oppDiffs = make(map[difficulty.Modifier][]oppai.DiffCalc)
+	ruleset.strains = make(map[difficulty.Modifier][]StrainPoint)
 
 	file, err := os.Open(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.File))
 
@@ -153,7 +165,7 @@ func NewOsuRuleset(beatMap *beatmap.BeatMap, cursors []*graphics.Cursor, mods []
 	// so we need to subtract a small amount from the value to have proper scoreMultiplier in edge cases (like 4.5 before rounding)
 	ruleset.scoreMultiplier = math.Round(float64((float32(beatMap.Diff.GetHPDrain())+float32(beatMap.Diff.GetOD())+float32(beatMap.Diff.GetCS())+bmath.ClampF32(float32(len(beatMap.HitObjects))/drainTime*8, 0, 16))/38*5) - 0.0000001)
 
-	ruleset.cursors = make(map[*graphics.Cursor]*subSet)
+	ruleset.cursors = make(map[*graphics.CursorState]*subSet)
 
 	var diffPlayers []*difficultyPlayer
 
@@ -172,13 +184,22 @@ func NewOsuRuleset(beatMap *beatmap.BeatMap, cursors []*graphics.Cursor, mods []
 			}
 
 			ruleset.oppDiffs[mods[i]] = diffs
+
+			// objects of the full map still hold strains from the last calculation
+			strains := make([]StrainPoint, 0)
+
+			for _, obj := range ruleset.oppaiMaps[len(ruleset.oppaiMaps)-1].Objects {
+				strains = append(strains, StrainPoint{obj.Time, obj.Strains[oppai.DiffAim], obj.Strains[oppai.DiffSpeed]})
+			}
+
+			ruleset.strains[mods[i]] = strains
 		}
 
 		hp := NewHealthProcessor(beatMap, diff)
 		hp.CalculateRate()
 		hp.ResetHp()
 
-		ruleset.cursors[cursor] = &subSet{player, 0, 100, 0, 0, 0, mods[i].GetScoreMultiplier(), 0, NONE, nil, &oppai.PPv2{}, make(map[HitResult]int64), 0, 0, hp}
+		ruleset.cursors[cursor] = &subSet{player, 0, 100, 0, 0, 0, mods[i].GetScoreMultiplier(), 0, NONE, nil, &oppai.PPv2{}, make(map[HitResult]int64), 0, 0, hp, 0, 0, 0}
 	}
 
 	for _, obj := range beatMap.HitObjects {
@@ -244,7 +265,7 @@ func (set *OsuRuleSet) Update(time int64) {
 	}
 
 	if len(set.queue) == 0 && len(set.processed) == 0 && !set.ended {
-		cs := make([]*graphics.Cursor, 0)
+		cs := make([]*graphics.CursorState, 0)
 		for c := range set.cursors {
 			cs = append(cs, c)
 		}
@@ -301,7 +322,7 @@ func humanize(number int64) string {
 	return humanized
 }
 
-func (set *OsuRuleSet) UpdateClickFor(cursor *graphics.Cursor, time int64) {
+func (set *OsuRuleSet) UpdateClickFor(cursor *graphics.CursorState, time int64) {
 	player := set.cursors[cursor].player
 
 	player.alreadyStolen = false
@@ -343,7 +364,7 @@ func (set *OsuRuleSet) UpdateClickFor(cursor *graphics.Cursor, time int64) {
 	}
 }
 
-func (set *OsuRuleSet) UpdateNormalFor(cursor *graphics.Cursor, time int64) {
+func (set *OsuRuleSet) UpdateNormalFor(cursor *graphics.CursorState, time int64) {
 	player := set.cursors[cursor].player
 
 	//wasSliderAlready := false
@@ -369,7 +390,7 @@ func (set *OsuRuleSet) UpdateNormalFor(cursor *graphics.Cursor, time int64) {
 	}
 }
 
-func (set *OsuRuleSet) UpdatePostFor(cursor *graphics.Cursor, time int64) {
+func (set *OsuRuleSet) UpdatePostFor(cursor *graphics.CursorState, time int64) {
 	player := set.cursors[cursor].player
 
 	if len(set.processed) > 0 {
@@ -381,7 +402,7 @@ func (set *OsuRuleSet) UpdatePostFor(cursor *graphics.Cursor, time int64) {
 	}
 }
 
-func (set *OsuRuleSet) SendResult(time int64, cursor *graphics.Cursor, number int64, x, y float32, result HitResult, raw bool, comboResult ComboResult) {
+func (set *OsuRuleSet) SendResult(time int64, cursor *graphics.CursorState, number int64, x, y float32, result HitResult, raw bool, comboResult ComboResult) {
 	if result == Ignore {
 		return
 	}
@@ -401,6 +422,17 @@ func (set *OsuRuleSet) SendResult(time int64, cursor *graphics.Cursor, number in
 		}
 	}
 
+	_, isCircle := set.beatMap.HitObjects[number].(*objects.Circle)
+	_, isSlider := set.beatMap.HitObjects[number].(*objects.Slider)
+
+	if (isCircle && result&BaseHits > 0) || (isSlider && result == SliderStart) {
+		hitError := float64(time - set.beatMap.HitObjects[number].GetBasicData().StartTime)
+
+		subSet.errorCount++
+		subSet.errorSum += hitError
+		subSet.errorSumSq += hitError * hitError
+	}
+
 	if result&BaseHitsM > 0 {
 		subSet.rawScore += result.ScoreValue()
 		subSet.hits[result]++
@@ -551,7 +583,7 @@ func (set *OsuRuleSet) CanBeHit(time int64, object HitObject, player *difficulty
 	return Click
 }
 
-func (set *OsuRuleSet) SetListener(listener func(cursor *graphics.Cursor, time int64, number int64, position vector.Vector2d, result HitResult, comboResult ComboResult, pp float64, score int64)) {
+func (set *OsuRuleSet) SetListener(listener func(cursor *graphics.CursorState, time int64, number int64, position vector.Vector2d, result HitResult, comboResult ComboResult, pp float64, score int64)) {
 	set.listener = listener
 }
 
@@ -559,17 +591,51 @@ func (set *OsuRuleSet) SetEndListener(endlistener func(time int64, number int64)
 	set.endlistener = endlistener
 }
 
-func (set *OsuRuleSet) GetResults(cursor *graphics.Cursor) (float64, int64, int64, Grade) {
+func (set *OsuRuleSet) GetResults(cursor *graphics.CursorState) (float64, int64, int64, Grade) {
 	subSet := set.cursors[cursor]
 	return subSet.accuracy, subSet.maxCombo, subSet.score, subSet.grade
 }
 
-func (set *OsuRuleSet) GetHP(cursor *graphics.Cursor) float64 {
+func (set *OsuRuleSet) GetHitCount(cursor *graphics.CursorState, result HitResult) int64 {
+	return set.cursors[cursor].hits[result]
+}
+
+// GetUnstableRate returns standard deviation of hit errors multiplied by 10.
+func (set *OsuRuleSet) GetUnstableRate(cursor *graphics.CursorState) float64 {
+	subSet := set.cursors[cursor]
+	if subSet.errorCount == 0 {
+		return 0
+	}
+
+	mean := subSet.errorSum / float64(subSet.errorCount)
+	variance := subSet.errorSumSq/float64(subSet.errorCount) - mean*mean
+
+	return 10 * math.Sqrt(math.Max(0, variance))
+}
+
+// GetFCAccuracy returns the accuracy player would have if all misses were 300s.
+func (set *OsuRuleSet) GetFCAccuracy(cursor *graphics.CursorState) float64 {
+	subSet := set.cursors[cursor]
+	if subSet.numObjects == 0 {
+		return 100
+	}
+
+	raw := 300*(subSet.hits[Hit300]+subSet.hits[Miss]) + 100*subSet.hits[Hit100] + 50*subSet.hits[Hit50]
+
+	return 100 * float64(raw) / float64(subSet.numObjects*300)
+}
+
+// GetStrains returns aim and speed strains of all objects, calculated with cursor's mods.
+func (set *OsuRuleSet) GetStrains(cursor *graphics.CursorState) []StrainPoint {
+	return set.strains[set.cursors[cursor].player.diff.Mods]
+}
+
+func (set *OsuRuleSet) GetHP(cursor *graphics.CursorState) float64 {
 	subSet := set.cursors[cursor]
 	return subSet.hp.Health / MaxHp
 }
 
-func (set *OsuRuleSet) GetPlayer(cursor *graphics.Cursor) *difficultyPlayer {
+func (set *OsuRuleSet) GetPlayer(cursor *graphics.CursorState) *difficultyPlayer {
 	subSet := set.cursors[cursor]
 	return subSet.player
 }
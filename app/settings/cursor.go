@@ -47,7 +47,7 @@ func initCursor() *cursor {
 }
 
 type cursor struct {
-	TrailStyle                  int
+	TrailStyle                  int //1, 1-4 - danser trails, 5 - skin cursor drawn like in osu!
 	Style23Speed                float64
 	Style4Shift                 float64
 	Colors                      *color
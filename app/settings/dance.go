@@ -4,8 +4,27 @@ var Dance *dance = initDance()
 
 func initDance() *dance {
 	return &dance{
-		Movers:             []string{"spline"},
-		Spinners:           []string{"circle"},
+		Movers:        []*Mover{NewMover("spline")},
+		Spinners:      []*Mover{NewMover("circle")},
+		Timeline:      []*TimelineEntry{},
+		Tapping:       []*Tapping{NewTapping("default")},
+		ExportReplays: false,
+		Humanizer: &Humanizer{
+			Enabled:        false,
+			Seed:           0,
+			UnstableRate:   100,
+			TargetAccuracy: 0,
+			MeanOffset:     -3,
+			AimNoise:       0.25,
+			JumpDistance:   150,
+			ReactionChance: 0.15,
+			ReactionDelay:  60,
+		},
+		Avoidance: &Avoidance{
+			Enabled:    false,
+			Distance:   60,
+			ReturnTime: 150,
+		},
 		DoSpinnersTogether: true,
 		SpinnerRadius:      100,
 		Battle:             false,
@@ -13,11 +32,11 @@ func initDance() *dance {
 		RandomSliderDance:  false,
 		TAGSliderDance:     false,
 		SliderDance2B:      true,
-		Bezier: &bezier{
+		Bezier: &Bezier{
 			Aggressiveness:       60,
 			SliderAggressiveness: 3,
 		},
-		Flower: &flower{
+		Flower: &Flower{
 			AngleOffset:        90,
 			DistanceMult:       0.666,
 			StreamAngleOffset:  90,
@@ -25,28 +44,52 @@ func initDance() *dance {
 			LongJumpMult:       0.7,
 			LongJumpOnEqualPos: false,
 		},
-		HalfCircle: &circular{
+		HalfCircle: &Circular{
 			RadiusMultiplier: 1,
 			StreamTrigger:    130,
 		},
-		Spline: &spline{
+		Spline: &Spline{
 			RotationalForce:  false,
 			StreamHalfCircle: true,
 			StreamWobble:     true,
 			WobbleScale:      0.67,
 		},
-		Momentum: &momentum{
+		Momentum: &Momentum{
 			SkipStackAngles: false,
 			RestrictAngle:   80,
 			DistanceMult:    0.666,
 			DistanceMultEnd: 0.666,
 		},
+		Spring: &Spring{
+			Frequency: 1.5,
+			Damping:   0.35,
+			Lookahead: 0.3,
+		},
+		Lissajous: &Lissajous{
+			MinGap:     400,
+			Radius:     50,
+			FrequencyX: 3,
+			FrequencyY: 2,
+			Phase:      90,
+			Speed:      1,
+		},
+		Scripted: &Scripted{
+			Variables: []string{
+				"handle = distance / 3",
+			},
+			Position: "bezier(startPos, startPos + polar(startAngle, handle), endPos - polar(endAngle, handle), endPos, 0.5 - cos(t * pi) / 2)",
+		},
 	}
 }
 
 type dance struct {
-	Movers             []string
-	Spinners           []string
+	Movers             []*Mover
+	Spinners           []*Mover
+	Timeline           []*TimelineEntry
+	Tapping            []*Tapping
+	ExportReplays      bool
+	Humanizer          *Humanizer
+	Avoidance          *Avoidance
 	DoSpinnersTogether bool
 	SpinnerRadius      float64
 	Battle             bool
@@ -54,18 +97,112 @@ type dance struct {
 	RandomSliderDance  bool
 	TAGSliderDance     bool
 	SliderDance2B      bool
-	Bezier             *bezier
-	Flower             *flower
-	HalfCircle         *circular
-	Spline             *spline
-	Momentum           *momentum
+	Bezier             *Bezier
+	Flower             *Flower
+	HalfCircle         *Circular
+	Spline             *Spline
+	Momentum           *Momentum
+	Spring             *Spring
+	Lissajous          *Lissajous
+	Scripted           *Scripted
+}
+
+// TimelineEntry switches movers for a part of the map. Entries are checked in order and the first matching one is used,
+// objects outside of all entries are handled by cursor's movers from Dance.Movers and Dance.Spinners.
+type TimelineEntry struct {
+	// "time" - from StartTime to EndTime (in ms, EndTime = 0 means the end of the map),
+	// "kiai" - kiai sections,
+	// "section" - part of the map between breaks, Section 0 is before the first break, negative values count from the end
+	Type string
+
+	StartTime int64 `json:",omitempty"`
+	EndTime   int64 `json:",omitempty"`
+	Section   int   `json:",omitempty"`
+
+	// Mover used for movements to objects inside the entry, cursor's mover is kept if it's not set
+	Mover *Mover `json:",omitempty"`
+
+	// Spinner movers used for consecutive spinners inside the entry
+	Spinners []*Mover `json:",omitempty"`
+}
+
+// Tapping describes how a cursor presses keys, entries of Dance.Tapping are assigned to cursors the same way as Dance.Movers.
+type Tapping struct {
+	// "default" - left key if the object starts less than 140ms after the previous one ended, right otherwise,
+	// "alternate" - alternate keys on every object,
+	// "singletap" - left key only, right key is used only if the left one is still held,
+	// "threshold" - singletap, but alternate objects faster than ThresholdBPM (1/4 notes) or further apart than ThresholdSpacing
+	Style string
+
+	ThresholdBPM     float64
+	ThresholdSpacing float64 // in osu!pixels, 0 disables spacing threshold
+
+	// Keep the key pressed until slider's end, otherwise sliders are tapped like circles
+	HoldSliders bool
+
+	// Use mouse buttons instead of keys
+	MouseButtons bool
+
+	// Time after which a tapped key is released, in ms
+	ReleaseTime float64
+}
+
+func NewTapping(style string) *Tapping {
+	return &Tapping{
+		Style:            style,
+		ThresholdBPM:     180,
+		ThresholdSpacing: 0,
+		HoldSliders:      true,
+		MouseButtons:     false,
+		ReleaseTime:      50,
+	}
+}
+
+// Humanizer makes danser's cursors imperfect, plays are scored and shown with the score overlay.
+type Humanizer struct {
+	Enabled bool
+
+	// Seed of the random generator, 0 uses the session seed (-seed). It's logged so the play can be repeated
+	Seed int64
+
+	// Target unstable rate, ignored if TargetAccuracy is set
+	UnstableRate float64
+
+	// Target accuracy in percent, hit error spread is fitted to it using map's hit windows. 0 uses UnstableRate instead
+	TargetAccuracy float64
+
+	// Mean hit error in ms, negative values hit early
+	MeanOffset float64
+
+	// Standard deviation of aim offsets as a fraction of circle radius, offsets never leave the circle
+	AimNoise float64
+
+	// Movements longer than this (in osu!pixels) are treated as jumps
+	JumpDistance float64
+
+	// Chance (0-1) of a delayed reaction on a jump, the cursor then moves faster to catch up
+	ReactionChance float64
+
+	// Maximum reaction delay in ms
+	ReactionDelay float64
 }
 
-type bezier struct {
+// Avoidance bends paths of TAG cursors away from each other. Cursors that are about to hit or are holding an object are never moved.
+type Avoidance struct {
+	Enabled bool
+
+	// Cursors closer than this (in osu!pixels) push each other away
+	Distance float64
+
+	// Time in ms in which a cursor leaves its path after an object and returns to it before the next one
+	ReturnTime float64
+}
+
+type Bezier struct {
 	Aggressiveness, SliderAggressiveness float64
 }
 
-type flower struct {
+type Flower struct {
 	AngleOffset        float64
 	DistanceMult       float64
 	StreamAngleOffset  float64
@@ -74,21 +211,57 @@ type flower struct {
 	LongJumpOnEqualPos bool
 }
 
-type circular struct {
+type Circular struct {
 	RadiusMultiplier float64
 	StreamTrigger    int64
 }
 
-type spline struct {
+type Spline struct {
 	RotationalForce  bool
 	StreamHalfCircle bool
 	StreamWobble     bool
 	WobbleScale      float64
 }
 
-type momentum struct {
+type Momentum struct {
 	SkipStackAngles bool
 	RestrictAngle   float64
 	DistanceMult    float64
 	DistanceMultEnd float64
 }
+
+type Spring struct {
+	// Natural frequency of the spring in Hz, it's lowered on gaps longer than 0.45/Frequency seconds so the cursor can always reach the next object
+	Frequency float64
+
+	// Damping ratio, below 1 the cursor swings past the anchor, 1 and above it doesn't
+	Damping float64
+
+	// How far (0-1) the spring's anchor is moved from the next object towards the one after it
+	Lookahead float64
+}
+
+type Lissajous struct {
+	// Gaps shorter than this (in ms) are plain eased lines
+	MinGap int64
+
+	// Size of the figure in osu!pixels
+	Radius float64
+
+	// Frequency ratio of the figure, 3 and 2 make a pretzel-like shape
+	FrequencyX, FrequencyY float64
+
+	// Phase shift of the X axis in degrees
+	Phase float64
+
+	// Loops per second
+	Speed float64
+}
+
+type Scripted struct {
+	// Helper variables written as "name = expression", evaluated in order before Position
+	Variables []string
+
+	// Vector expression giving cursor position between two objects
+	Position string
+}
@@ -24,7 +24,38 @@ func initGameplay() *gameplay {
 			Scale:   1.0,
 			Opacity: 1.0,
 		},
-		ProgressBar: "Pie",
+		HPBar: &hudElement{
+			Show:    true,
+			Scale:   1.0,
+			Opacity: 1.0,
+		},
+		PPCounter: &hudElement{
+			Show:    true,
+			Scale:   1.0,
+			Opacity: 1.0,
+		},
+		UnstableRate: &hudElement{
+			Show:    false,
+			Scale:   1.0,
+			Opacity: 1.0,
+		},
+		HitCounter: &hudElement{
+			Show:    false,
+			Scale:   1.0,
+			Opacity: 1.0,
+		},
+		AccuracyIfFC: &hudElement{
+			Show:    false,
+			Scale:   1.0,
+			Opacity: 1.0,
+		},
+		StrainGraph: &hudElement{
+			Show:    false,
+			Scale:   1.0,
+			Opacity: 1.0,
+		},
+		ProgressBar:         "Pie",
+		ProgressBarPosition: "Bottom",
 		Boundaries: &boundaries{
 			Enabled:         true,
 			BorderThickness: 1,
@@ -50,8 +81,15 @@ type gameplay struct {
 	Score         *hudElement
 	ComboCounter  *hudElement
 	KeyOverlay    *hudElement
+	HPBar         *hudElement
+	PPCounter     *hudElement
+	UnstableRate  *hudElement
+	HitCounter    *hudElement
+	AccuracyIfFC  *hudElement
+	StrainGraph   *hudElement
 
-	ProgressBar string
+	ProgressBar         string // Pie, Bar or StrainGraph
+	ProgressBarPosition string // Top or Bottom, used by StrainGraph
 
 	Boundaries *boundaries
 }
@@ -73,4 +111,11 @@ type hudElement struct {
 	Show    bool
 	Scale   float64
 	Opacity float64
+
+	// Anchor is one of TopLeft, TopCentre, TopRight, CentreLeft, Centre, CentreRight, BottomLeft, BottomCentre, BottomRight.
+	// Empty keeps element's default place.
+	Anchor   string
+	XOffset  float64
+	YOffset  float64
+	Rotation float64 // in degrees, clockwise
 }
@@ -21,6 +21,9 @@ func initGeneral() *general {
 		OsuSongsDir:       filepath.Join(osuBaseDir, "Songs"),
 		OsuSkinsDir:       filepath.Join(osuBaseDir, "Skins"),
 		DiscordPresenceOn: true,
+
+		WatchSongsDir:        true,
+		SongsDirPollInterval: 5,
 	}
 }
 
@@ -34,4 +37,10 @@ type general struct {
 
 	// Whether discord should show that danser is on
 	DiscordPresenceOn bool
+
+	// Whether danser should watch OsuSongsDir for new or changed beatmaps while running
+	WatchSongsDir bool
+
+	// How often (in seconds) OsuSongsDir should be checked when native filesystem notifications are unavailable
+	SongsDirPollInterval float64
 }
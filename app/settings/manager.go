@@ -21,6 +21,7 @@ func initStorage() {
 		Playfield: Playfield,
 		Dance:     Dance,
 		Knockout:  Knockout,
+		Recording: Recording,
 	}
 }
 
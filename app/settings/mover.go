@@ -0,0 +1,58 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Mover is an entry of Dance.Movers or Dance.Spinners.
+// It can be written either as a plain name ("spline") or as an object with a per-cursor parameter block:
+// {"Name": "spline", "Config": {"WobbleScale": 1.0}}. Parameters that are not given are taken from the global section (like Dance.Spline).
+type Mover struct {
+	Name   string
+	Config json.RawMessage `json:",omitempty"`
+}
+
+func NewMover(name string) *Mover {
+	return &Mover{Name: name}
+}
+
+func (m *Mover) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		m.Name = name
+		m.Config = nil
+		return nil
+	}
+
+	type plain Mover
+	return json.Unmarshal(data, (*plain)(m))
+}
+
+func (m Mover) MarshalJSON() ([]byte, error) {
+	if len(m.Config) == 0 {
+		return json.Marshal(m.Name)
+	}
+
+	type plain Mover
+	return json.Marshal(plain(m))
+}
+
+// GetName returns the lowercase mover name used for registry lookups.
+func (m *Mover) GetName() string {
+	return strings.ToLower(strings.TrimSpace(m.Name))
+}
+
+// DecodeConfig overlays the parameter block on target, which should already hold default values.
+// Unknown parameters are reported as errors.
+func (m *Mover) DecodeConfig(target interface{}) error {
+	if len(m.Config) == 0 || target == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(m.Config))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(target)
+}
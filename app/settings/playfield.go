@@ -61,6 +61,19 @@ func initPlayfield() *playfield {
 			Blur:              0.6,
 			Power:             0.7,
 		},
+		MotionBlur: &motionBlur{
+			Enabled:   false,
+			Subframes: 8,
+			Weighting: "gaussian",
+		},
+		PostProcess: &postProcess{
+			Enabled: false,
+			Passes:  []*PostProcessPass{},
+		},
+		Symmetry: &symmetry{
+			Mode:   "rotate",
+			Layout: []*PlayfieldCopy{},
+		},
 	}
 }
 
@@ -79,6 +92,30 @@ type playfield struct {
 	Background                   *background
 	Logo                         *logo
 	Bloom                        *bloom
+	MotionBlur                   *motionBlur
+	PostProcess                  *postProcess
+	Symmetry                     *symmetry
+}
+
+// Symmetry controls how copies of the playfield (set with -cursors) are laid out
+type symmetry struct {
+	// "rotate" - copies are rotated around the center,
+	// "mirror-horizontal" / "mirror-vertical" - every second copy is flipped left to right / upside down,
+	// "mirror-both" - copies are mirrored along both axes in turn (4 copies make a full set),
+	// "kaleidoscope" - copies are rotated like in "rotate" and every second one is mirrored, even number of copies looks best
+	Mode string
+
+	// Scale and offset of each copy, applied after Mode. Entries are used in turn, empty list leaves copies in the center.
+	// For example 4 copies with scale 0.5 and offsets (-128, -96), (128, -96), (-128, 96), (128, 96) make a 2x2 collage
+	Layout []*PlayfieldCopy
+}
+
+type PlayfieldCopy struct {
+	// Scale of the copy, 0 is treated as 1
+	Scale float64
+
+	// Offset of the copy in osu!pixels
+	OffsetX, OffsetY float64
 }
 
 type seizure struct {
@@ -152,3 +189,35 @@ type bloom struct {
 	Blur              float64
 	Power             float64
 }
+
+// Motion blur is applied only when recording with -record
+type motionBlur struct {
+	Enabled bool
+
+	// How many frames are rendered and blended together into one recorded frame
+	Subframes int
+
+	// How subframes are weighted: "flat" - equally, "gaussian" - the middle of the frame is the sharpest,
+	// "front" - weights grow towards the newest subframe, leaving a fading trail behind moving objects
+	Weighting string
+}
+
+// PostProcess runs the whole frame, except debug text, through user GLSL fragment shaders in the order they are listed.
+// Shaders use "#version 330", read the frame from "uniform sampler2DArray tex" at vec3(tex_coord, 0)
+// with "in vec2 tex_coord" and write to "out vec4 color".
+// Built-in uniforms (declare the ones you need): float time - map time in seconds, float beatScale - beat scale
+// of the playfield (1 when idle), bool kiai - whether a kiai section is active, vec2 resolution - frame size in pixels.
+// Shaders that fail to compile are reported in the log and skipped.
+type postProcess struct {
+	Enabled bool
+	Passes  []*PostProcessPass
+}
+
+type PostProcessPass struct {
+	// Path to the fragment shader file
+	Shader string
+
+	// Values of the shader's own uniforms, a number for float, int and bool uniforms or a list of 2-4 numbers for vectors,
+	// for example {"strength": 0.5, "tint": [1, 0.8, 0.6]}
+	Uniforms map[string]interface{}
+}
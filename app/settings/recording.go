@@ -0,0 +1,30 @@
+package settings
+
+var Recording = initRecording()
+
+func initRecording() *recording {
+	return &recording{
+		FPS:            60,
+		Output:         "command",
+		EncoderCommand: "ffmpeg -y -f rawvideo -pixel_format rgb24 -video_size {width}x{height} -framerate {fps} -i - -c:v libx264 -preset medium -crf 18 -pix_fmt yuv420p {output}",
+		MuxCommand:     "",
+	}
+}
+
+type recording struct {
+	// Frames per second of the recorded video, the player is updated with a fixed time step regardless of how long rendering takes
+	FPS float64
+
+	// Where frames go: "command" streams raw rgb24 frames to EncoderCommand's standard input, "png" writes a numbered image sequence
+	// to the directory given in -record and "y4m" writes a single uncompressed YUV4MPEG2 file
+	Output string
+
+	// Encoder started in "command" mode. {width}, {height}, {fps} and {output} are replaced with frame size, FPS and -record value.
+	// Arguments containing spaces, like the path to the executable, can be put in double or single quotes
+	EncoderCommand string
+
+	// Optional command run after the recording is done, for example to put the video and audio together:
+	// "ffmpeg -y -i {video} -i {audio} -c:v copy -c:a aac -shortest {name}-final.mp4". {video} and {audio} are replaced with output paths,
+	// {name} with the video path without extension
+	MuxCommand string
+}
@@ -12,6 +12,7 @@ type fileformat struct {
 	Playfield *playfield
 	Dance     *dance
 	Knockout  *knockout
+	Recording *recording
 }
 
 var DEBUG = false
@@ -24,3 +25,5 @@ var DIVIDES = 2
 var SPEED = 1.0
 var PITCH = 1.0
 var TAG = 1
+var SEED int64 = 0
+var RECORD = false
@@ -0,0 +1,85 @@
+package skin
+
+import (
+	"archive/zip"
+	"fmt"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/app/utils"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImportSkins extracts all skin archives (.osk) found in OsuSkinsDir.
+func ImportSkins() {
+	files, err := ioutil.ReadDir(settings.General.OsuSkinsDir)
+	if err != nil {
+		return
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.EqualFold(filepath.Ext(f.Name()), ".osk") {
+			continue
+		}
+
+		ImportArchive(filepath.Join(settings.General.OsuSkinsDir, f.Name()))
+	}
+}
+
+// ImportArchive safely extracts a skin archive (.osk) into a new directory of OsuSkinsDir named after the archive.
+// Archives are checked the same way as beatmap imports, the archive is deleted on success and quarantined otherwise.
+// Returns the name of the created skin, empty if nothing was extracted.
+func ImportArchive(osPathname string) (dir string) {
+	var errs []error
+
+	defer func() {
+		for _, err := range errs {
+			log.Println("Import error:", filepath.Base(osPathname)+":", err)
+		}
+
+		if len(errs) > 0 {
+			utils.QuarantineArchive(osPathname, errs)
+		} else if err := os.Remove(osPathname); err != nil {
+			log.Println("Failed to remove imported archive:", err)
+		}
+	}()
+
+	skinsDir := filepath.Dir(osPathname)
+
+	r, err := zip.OpenReader(osPathname)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+
+	defer r.Close()
+
+	if errs = utils.ValidateArchive(&r.Reader, filepath.Join(skinsDir, "archive")); len(errs) > 0 {
+		return
+	}
+
+	dir = freeSkinDir(skinsDir, strings.TrimSuffix(filepath.Base(osPathname), filepath.Ext(osPathname)))
+
+	log.Println("Unpacking", osPathname, "to", dir)
+
+	// nothing is left in the skin directory if extraction fails
+	if _, errs = utils.ExtractArchive(&r.Reader, filepath.Join(skinsDir, dir)); len(errs) > 0 {
+		dir = ""
+	}
+
+	return
+}
+
+func freeSkinDir(skinsDir, name string) string {
+	dir := name
+
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(skinsDir, dir)); os.IsNotExist(err) {
+			return dir
+		}
+
+		dir = fmt.Sprintf("%s (%d)", name, i)
+	}
+}
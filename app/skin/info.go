@@ -28,14 +28,18 @@ type SkinInfo struct {
 
 	LayeredHitSounds bool
 
-	//skipping combo bursts
+	ComboBurstRandom       bool
+	CustomComboBurstSounds []int64
 
-	//skipping cursor settings for now
-	//renderer for old cursor trails may come in the future
+	CursorCentre      bool
+	CursorExpand      bool
+	CursorRotate      bool
+	CursorTrailRotate bool
 
 	ComboColors []color.Color
 
-	//slider style unnecessary
+	// 1 - flat body, 2 - gradient
+	SliderStyle int
 
 	SliderBallTint      bool
 	SliderBallFlip      bool
@@ -43,7 +47,8 @@ type SkinInfo struct {
 	SliderTrackOverride *color.Color
 	SliderBall          *color.Color
 
-	InputOverlayText color.Color
+	InputOverlayText  color.Color
+	SpinnerBackground color.Color
 
 	//hit circle font settings
 	HitCirclePrefix             string
@@ -63,23 +68,29 @@ func newDefaultInfo() *SkinInfo {
 	return &SkinInfo{
 		Name:                     "",
 		Author:                   "",
-		Version:                  2.7,
+		Version:                  latestVersion,
 		AnimationFramerate:       -1,
-		SpinnerFadePlayfield:     true,
+		SpinnerFadePlayfield:     false,
 		SpinnerNoBlink:           false,
 		SpinnerFrequencyModulate: true,
 		LayeredHitSounds:         true,
+		CursorCentre:             true,
+		CursorExpand:             true,
+		CursorRotate:             true,
+		CursorTrailRotate:        true,
 		ComboColors: []color.Color{
 			color.NewIRGB(255, 192, 0),
 			color.NewIRGB(0, 202, 0),
 			color.NewIRGB(18, 124, 255),
 			color.NewIRGB(242, 24, 57),
 		},
+		SliderStyle:                 2,
 		SliderBallTint:              false,
 		SliderBallFlip:              false,
 		SliderBorder:                color.NewL(1),
 		SliderTrackOverride:         nil,
 		InputOverlayText:            color.NewL(1),
+		SpinnerBackground:           color.NewIRGB(100, 100, 100),
 		HitCirclePrefix:             "default",
 		HitCircleOverlap:            -2,
 		HitCircleOverlayAboveNumber: false,
@@ -105,13 +116,16 @@ func tokenize(line, delimiter string) []string {
 		line = line[:index]
 	}
 
-	if strings.HasPrefix(line, "//") || !strings.Contains(line, delimiter) {
+	if !strings.Contains(line, delimiter) {
 		return nil
 	}
-	divided := strings.Split(line, delimiter)
+
+	// only the first delimiter separates the key, names can contain it as well
+	divided := strings.SplitN(line, delimiter, 2)
 	for i, a := range divided {
 		divided[i] = strings.TrimSpace(a)
 	}
+
 	return divided
 }
 
@@ -148,9 +162,15 @@ func ParseColor(text, errType string) color.Color {
 	return color
 }
 
-func LoadInfo(path string) (*SkinInfo, error) {
+func LoadInfo(path string) (result *SkinInfo, err error) {
+	// ParseFloat and ParseColor panic on malformed values, the skin is reported as broken instead
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%s: %v", path, r)
+		}
+	}()
+
 	var file io.ReadCloser
-	var err error
 
 	if strings.HasPrefix(path, "assets") {
 		file, err = assets.Open(path)
@@ -175,8 +195,18 @@ func LoadInfo(path string) (*SkinInfo, error) {
 
 	colorsI := make([]colorI, 0)
 
+	// skin.ini without Version is treated as an old skin
+	info.Version = 1.0
+
+	section := ""
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\uFEFF"))
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			continue
+		}
 
 		tokenized := tokenize(line, ":")
 
@@ -184,61 +214,18 @@ func LoadInfo(path string) (*SkinInfo, error) {
 			continue
 		}
 
-		switch tokenized[0] {
-		case "Name":
-			info.Name = tokenized[1]
-		case "Author":
-			info.Author = tokenized[1]
-		case "Version":
-			if tokenized[1] == "latest" {
-				info.Version = latestVersion
-			} else {
-				info.Version = ParseFloat(tokenized[1], tokenized[0])
+		switch section {
+		case "General":
+			parseGeneral(info, tokenized[0], tokenized[1])
+		case "Colours":
+			if index, ok := parseColours(info, tokenized[0], tokenized[1]); ok {
+				colorsI = append(colorsI, colorI{
+					index: index,
+					color: ParseColor(tokenized[1], tokenized[0]),
+				})
 			}
-		case "AnimationFramerate":
-			info.AnimationFramerate = ParseFloat(tokenized[1], tokenized[0])
-		case "SpinnerFadePlayfield":
-			info.SpinnerFadePlayfield = tokenized[1] == "1"
-		case "SpinnerNoBlink":
-			info.SpinnerNoBlink = tokenized[1] == "1"
-		case "SpinnerFrequencyModulate":
-			info.SpinnerFrequencyModulate = tokenized[1] == "1"
-		case "LayeredHitSounds":
-			info.LayeredHitSounds = tokenized[1] == "1"
-		case "Combo1", "Combo2", "Combo3", "Combo4", "Combo5", "Combo6", "Combo7", "Combo8":
-			index, _ := strconv.ParseInt(strings.TrimPrefix(tokenized[0], "Combo"), 10, 64)
-			colorsI = append(colorsI, colorI{
-				index: int(index),
-				color: ParseColor(tokenized[1], tokenized[0]),
-			})
-		case "AllowSliderBallTint":
-			info.SliderBallTint = tokenized[1] == "1"
-		case "SliderBallFlip":
-			info.SliderBallFlip = tokenized[1] == "1"
-		case "SliderBorder":
-			info.SliderBorder = ParseColor(tokenized[1], tokenized[0])
-		case "SliderTrackOverride":
-			col := ParseColor(tokenized[1], tokenized[0])
-			info.SliderTrackOverride = &col
-		case "SliderBall":
-			col := ParseColor(tokenized[1], tokenized[0])
-			info.SliderBall = &col
-		case "InputOverlayText":
-			info.InputOverlayText = ParseColor(tokenized[1], tokenized[0])
-		case "HitCirclePrefix":
-			info.HitCirclePrefix = tokenized[1]
-		case "HitCircleOverlap":
-			info.HitCircleOverlap = ParseFloat(tokenized[1], tokenized[0])
-		case "HitCircleOverlayAboveNumber", "HitCircleOverlayAboveNumer":
-			info.HitCircleOverlayAboveNumber = tokenized[1] == "1"
-		case "ScorePrefix":
-			info.ScorePrefix = tokenized[1]
-		case "ScoreOverlap":
-			info.ScoreOverlap = ParseFloat(tokenized[1], tokenized[0])
-		case "ComboPrefix":
-			info.ComboPrefix = tokenized[1]
-		case "ComboOverlap":
-			info.ComboOverlap = ParseFloat(tokenized[1], tokenized[0])
+		case "Fonts":
+			parseFonts(info, tokenized[0], tokenized[1])
 		}
 	}
 
@@ -256,3 +243,98 @@ func LoadInfo(path string) (*SkinInfo, error) {
 
 	return info, nil
 }
+
+func parseGeneral(info *SkinInfo, key, value string) {
+	switch key {
+	case "Name":
+		info.Name = value
+	case "Author":
+		info.Author = value
+	case "Version":
+		if value == "latest" {
+			info.Version = latestVersion
+		} else {
+			info.Version = ParseFloat(value, key)
+		}
+	case "AnimationFramerate":
+		info.AnimationFramerate = ParseFloat(value, key)
+	case "SpinnerFadePlayfield":
+		info.SpinnerFadePlayfield = value == "1"
+	case "SpinnerNoBlink":
+		info.SpinnerNoBlink = value == "1"
+	case "SpinnerFrequencyModulate":
+		info.SpinnerFrequencyModulate = value == "1"
+	case "LayeredHitSounds":
+		info.LayeredHitSounds = value == "1"
+	case "ComboBurstRandom":
+		info.ComboBurstRandom = value == "1"
+	case "CustomComboBurstSounds":
+		info.CustomComboBurstSounds = info.CustomComboBurstSounds[:0]
+
+		for _, v := range strings.Split(value, ",") {
+			if combo, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil && combo > 0 {
+				info.CustomComboBurstSounds = append(info.CustomComboBurstSounds, combo)
+			}
+		}
+	case "CursorCentre":
+		info.CursorCentre = value == "1"
+	case "CursorExpand":
+		info.CursorExpand = value == "1"
+	case "CursorRotate":
+		info.CursorRotate = value == "1"
+	case "CursorTrailRotate":
+		info.CursorTrailRotate = value == "1"
+	case "SliderStyle":
+		if value == "1" {
+			info.SliderStyle = 1
+		} else {
+			info.SliderStyle = 2
+		}
+	case "AllowSliderBallTint":
+		info.SliderBallTint = value == "1"
+	case "SliderBallFlip":
+		info.SliderBallFlip = value == "1"
+	case "HitCircleOverlayAboveNumber", "HitCircleOverlayAboveNumer":
+		info.HitCircleOverlayAboveNumber = value == "1"
+	}
+}
+
+// parseColours returns the combo index if the key is a combo colour, those have to be sorted later
+func parseColours(info *SkinInfo, key, value string) (int, bool) {
+	switch key {
+	case "Combo1", "Combo2", "Combo3", "Combo4", "Combo5", "Combo6", "Combo7", "Combo8":
+		index, _ := strconv.ParseInt(strings.TrimPrefix(key, "Combo"), 10, 64)
+		return int(index), true
+	case "SliderBorder":
+		info.SliderBorder = ParseColor(value, key)
+	case "SliderTrackOverride":
+		col := ParseColor(value, key)
+		info.SliderTrackOverride = &col
+	case "SliderBall":
+		col := ParseColor(value, key)
+		info.SliderBall = &col
+	case "InputOverlayText":
+		info.InputOverlayText = ParseColor(value, key)
+	case "SpinnerBackground":
+		info.SpinnerBackground = ParseColor(value, key)
+	}
+
+	return 0, false
+}
+
+func parseFonts(info *SkinInfo, key, value string) {
+	switch key {
+	case "HitCirclePrefix":
+		info.HitCirclePrefix = strings.ReplaceAll(value, "\\", "/")
+	case "HitCircleOverlap":
+		info.HitCircleOverlap = ParseFloat(value, key)
+	case "ScorePrefix":
+		info.ScorePrefix = strings.ReplaceAll(value, "\\", "/")
+	case "ScoreOverlap":
+		info.ScoreOverlap = ParseFloat(value, key)
+	case "ComboPrefix":
+		info.ComboPrefix = strings.ReplaceAll(value, "\\", "/")
+	case "ComboOverlap":
+		info.ComboOverlap = ParseFloat(value, key)
+	}
+}
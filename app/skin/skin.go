@@ -9,6 +9,7 @@ import (
 	"github.com/wieku/danser-go/framework/bass"
 	"github.com/wieku/danser-go/framework/graphics/texture"
 	"log"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -71,8 +72,7 @@ func checkInit() {
 		fallback()
 	} else {
 		var err error
-		info, err = LoadInfo(filepath.Join(settings.General.OsuSkinsDir, CurrentSkin, "skin.ini"))
-		if err != nil {
+		if info, err = loadSkinInfo(CurrentSkin); err != nil {
 			log.Println("SkinManager:", CurrentSkin, "is corrupted, falling back to default...")
 			fallback()
 		}
@@ -81,6 +81,24 @@ func checkInit() {
 	log.Println(fmt.Sprintf("SkinManager: Skin \"%s\" loaded.", CurrentSkin))
 }
 
+// loadSkinInfo reads skin.ini of a skin from OsuSkinsDir
+func loadSkinInfo(name string) (*SkinInfo, error) {
+	if name == defaultName {
+		return LoadInfo(filepath.Join("assets", "default-skin", "skin.ini"))
+	}
+
+	info, err := LoadInfo(filepath.Join(settings.General.OsuSkinsDir, name, "skin.ini"))
+	if _, dirErr := os.Stat(filepath.Join(settings.General.OsuSkinsDir, name)); dirErr == nil && os.IsNotExist(err) {
+		// osu! treats skins without skin.ini as the latest version
+		info = newDefaultInfo()
+		info.Name = name
+
+		return info, nil
+	}
+
+	return info, err
+}
+
 func GetInfo() *SkinInfo {
 	checkInit()
 	return info
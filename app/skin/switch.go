@@ -0,0 +1,79 @@
+package skin
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/graphics/font"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/bass"
+	"github.com/wieku/danser-go/framework/graphics/texture"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+)
+
+// ListSkins returns the default skin followed by all skin directories in OsuSkinsDir.
+func ListSkins() []string {
+	skins := []string{defaultName}
+
+	files, err := ioutil.ReadDir(settings.General.OsuSkinsDir)
+	if err != nil {
+		return skins
+	}
+
+	var dirs []string
+
+	for _, f := range files {
+		if f.IsDir() && f.Name() != defaultName {
+			dirs = append(dirs, f.Name())
+		}
+	}
+
+	sort.Strings(dirs)
+
+	return append(skins, dirs...)
+}
+
+// SwitchSkin drops all textures, fonts and samples of the current skin and loads the given one.
+// If skin.ini of the new skin can't be read, an error is returned and the current skin is kept.
+// Otherwise regions obtained earlier become invalid, so everything that holds them has to reload its textures afterwards.
+// It has to be called on the main thread while nothing else reads from the skin.
+func SwitchSkin(name string) error {
+	checkInit()
+
+	name = filepath.Base(name)
+
+	newInfo, err := loadSkinInfo(name)
+	if err != nil {
+		return fmt.Errorf("failed to load skin \"%s\": %w", name, err)
+	}
+
+	log.Println("SkinManager: Switching skin to:", name)
+
+	if atlas != nil {
+		atlas.Dispose()
+		atlas = nil
+	}
+
+	for _, tx := range singleTextures {
+		tx.Dispose()
+	}
+
+	singleTextures = make(map[string]*texture.TextureSingle)
+
+	skinCache = make(map[string]*texture.TextureRegion)
+	defaultCache = make(map[string]*texture.TextureRegion)
+	animationCache = make(map[string][]*texture.TextureRegion)
+	sourceCache = make(map[*texture.TextureRegion]Source)
+	fontCache = make(map[string]*font.Font)
+	sampleCache = make(map[string]*bass.Sample)
+
+	settings.Skin.CurrentSkin = name
+	CurrentSkin = name
+
+	info = newInfo
+
+	log.Println(fmt.Sprintf("SkinManager: Skin \"%s\" loaded.", CurrentSkin))
+
+	return nil
+}
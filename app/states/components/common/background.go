@@ -93,7 +93,8 @@ func (bg *Background) Update(time float64, x, y float64) {
 		bg.lastTime = time
 	}
 
-	if bg.storyboard != nil {
+	// when recording, the player updates the storyboard once per frame
+	if bg.storyboard != nil && !settings.RECORD {
 		if !bg.storyboard.IsThreadRunning() {
 			bg.storyboard.StartThread()
 		}
@@ -36,25 +36,34 @@ func NewHitObjectContainer(beatMap *beatmap.BeatMap) *HitObjectContainer {
 	container := new(HitObjectContainer)
 	container.beatMap = beatMap
 	container.objectQueue = beatMap.GetObjectsCopy()
-	container.spriteManager = sprite.NewSpriteManager()
 	container.renderables = make([]*renderableProxy, 0)
 
+	container.createFollowPoints()
+
+	return container
+}
+
+func (container *HitObjectContainer) createFollowPoints() {
+	container.spriteManager = sprite.NewSpriteManager()
+
+	hitObjects := container.beatMap.HitObjects
+
 	prempt := 800.0
 	postmt := 240.0
 	lineDist := 32.0
 
-	for i := 1; i < len(container.objectQueue); i++ {
-		_, ok1 := container.objectQueue[i-1].(*objects.Spinner)
-		_, ok2 := container.objectQueue[i].(*objects.Spinner)
-		if ok1 || ok2 || container.objectQueue[i].GetBasicData().NewCombo {
+	for i := 1; i < len(hitObjects); i++ {
+		_, ok1 := hitObjects[i-1].(*objects.Spinner)
+		_, ok2 := hitObjects[i].(*objects.Spinner)
+		if ok1 || ok2 || hitObjects[i].GetBasicData().NewCombo {
 			continue
 		}
 
-		prevTime := float64(container.objectQueue[i-1].GetBasicData().EndTime)
-		prevPos := container.objectQueue[i-1].GetBasicData().EndPos.Copy64()
+		prevTime := float64(hitObjects[i-1].GetBasicData().EndTime)
+		prevPos := hitObjects[i-1].GetBasicData().EndPos.Copy64()
 
-		nextTime := float64(container.objectQueue[i].GetBasicData().StartTime)
-		nextPos := container.objectQueue[i].GetBasicData().StartPos.Copy64()
+		nextTime := float64(hitObjects[i].GetBasicData().StartTime)
+		nextPos := hitObjects[i].GetBasicData().StartPos.Copy64()
 
 		vec := nextPos.Sub(prevPos)
 		duration := nextTime - prevTime
@@ -83,8 +92,18 @@ func NewHitObjectContainer(beatMap *beatmap.BeatMap) *HitObjectContainer {
 			container.spriteManager.Add(sprite)
 		}
 	}
+}
 
-	return container
+// ReloadTextures makes all objects and follow points use textures of the current skin, playback state is kept.
+func (container *HitObjectContainer) ReloadTextures() {
+	for _, o := range container.beatMap.HitObjects {
+		if r, ok := o.(objects.Renderable); ok {
+			r.ReloadTextures()
+		}
+	}
+
+	container.createFollowPoints()
+	container.spriteManager.Update(int64(container.lastTime))
 }
 
 func (container *HitObjectContainer) addProxy(proxy *renderableProxy) {
@@ -0,0 +1,69 @@
+package overlays
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+)
+
+var hudAnchors = map[string]vector.Vector2d{
+	"TopLeft":      bmath.Origin.TopLeft,
+	"TopCentre":    bmath.Origin.TopCentre,
+	"TopRight":     bmath.Origin.TopRight,
+	"CentreLeft":   bmath.Origin.CentreLeft,
+	"Centre":       bmath.Origin.Centre,
+	"CentreRight":  bmath.Origin.CentreRight,
+	"BottomLeft":   bmath.Origin.BottomLeft,
+	"BottomCentre": bmath.Origin.BottomCentre,
+	"BottomRight":  bmath.Origin.BottomRight,
+}
+
+// hudLayout describes where HUD element is drawn by default: the screen anchor it's attached to and the area it occupies
+type hudLayout struct {
+	anchor vector.Vector2d
+	pos    vector.Vector2d
+	size   vector.Vector2d
+}
+
+func newHudLayout(anchor vector.Vector2d, x, y, width, height float64) hudLayout {
+	return hudLayout{anchor, vector.NewVec2d(x, y), vector.NewVec2d(width, height)}
+}
+
+// pivot returns the point of element's area that corresponds to given origin
+func (layout hudLayout) pivot(origin vector.Vector2d) vector.Vector2d {
+	return layout.pos.Add(layout.size.Mult(origin.AddS(1, 1).Scl(0.5)))
+}
+
+func (overlay *ScoreOverlay) anchorPoint(origin vector.Vector2d) vector.Vector2d {
+	return vector.NewVec2d((origin.X+1)/2*overlay.ScaledWidth, (origin.Y+1)/2*overlay.ScaledHeight)
+}
+
+// layoutCamera returns projection that moves element from its default place to the configured anchor.
+// Element's corner matching the anchor is placed on it, keeping the margin element had from the edges of its default anchor, then offset and rotation are applied around that corner.
+func (overlay *ScoreOverlay) layoutCamera(layout hudLayout, anchor string, xOffset, yOffset, rotation float64) mgl32.Mat4 {
+	target, ok := hudAnchors[anchor]
+	if !ok {
+		target = layout.anchor
+	}
+
+	margin := layout.pivot(layout.anchor).Sub(overlay.anchorPoint(layout.anchor))
+
+	// margin makes sense only against the screen edge element was attached to
+	if target.X != layout.anchor.X {
+		margin.X = 0
+	}
+
+	if target.Y != layout.anchor.Y {
+		margin.Y = 0
+	}
+
+	dst := overlay.anchorPoint(target).Add(margin).AddS(xOffset, yOffset)
+	src := layout.pivot(target)
+
+	transform := mgl32.Translate3D(dst.X32(), dst.Y32(), 0).
+		Mul4(mgl32.HomogRotate3DZ(float32(rotation * math.Pi / 180))).
+		Mul4(mgl32.Translate3D(-src.X32(), -src.Y32(), 0))
+
+	return overlay.camera.GetProjectionView().Mul4(transform)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/wieku/danser-go/app/beatmap/difficulty"
 	"github.com/wieku/danser-go/app/bmath"
+	camera2 "github.com/wieku/danser-go/app/bmath/camera"
 	"github.com/wieku/danser-go/app/dance"
 	"github.com/wieku/danser-go/app/discord"
 	"github.com/wieku/danser-go/app/graphics"
@@ -12,6 +13,7 @@ import (
 	"github.com/wieku/danser-go/app/settings"
 	"github.com/wieku/danser-go/app/skin"
 	"github.com/wieku/danser-go/app/states/components/common"
+	"github.com/wieku/danser-go/app/states/components/overlays/play"
 	"github.com/wieku/danser-go/framework/graphics/batch"
 	"github.com/wieku/danser-go/framework/math/animation"
 	"github.com/wieku/danser-go/framework/math/animation/easing"
@@ -63,11 +65,11 @@ type bubble struct {
 	deathScale *animation.Glider
 }
 
-func newBubble(position vector.Vector2d, time int64, name string, combo int64, lastHit osu.HitResult, lastCombo osu.ComboResult) *bubble {
+func newBubble(random *rand.Rand, position vector.Vector2d, time int64, name string, combo int64, lastHit osu.HitResult, lastCombo osu.ComboResult) *bubble {
 	bub := new(bubble)
 	bub.name = name
-	deathShiftX := (rand.Float64() - 0.5) * 10
-	deathShiftY := (rand.Float64() - 0.5) * 10
+	deathShiftX := (random.Float64() - 0.5) * 10
+	deathShiftY := (random.Float64() - 0.5) * 10
 	bub.deathX = float64(position.X) + deathShiftX
 	bub.deathSlide = animation.NewGlider(0.0)
 	bub.deathFade = animation.NewGlider(0.0)
@@ -97,13 +99,18 @@ type KnockoutOverlay struct {
 	players      map[string]*knockoutPlayer
 	playersArray []*knockoutPlayer
 	deathBubbles []*bubble
-	names        map[*graphics.Cursor]string
+	names        map[*graphics.CursorState]string
 	lastTime     int64
 	//deaths     map[int64]int64
 	generator *rand.Rand
 	lastObj   int64
 
 	boundaries *common.Boundaries
+
+	strainGraph  *play.StrainGraph
+	graphCamera  *camera2.Camera
+	scaledWidth  float64
+	scaledHeight float64
 }
 
 func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverlay {
@@ -113,8 +120,8 @@ func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverl
 	overlay.players = make(map[string]*knockoutPlayer)
 	overlay.playersArray = make([]*knockoutPlayer, 0)
 	overlay.deathBubbles = make([]*bubble, 0)
-	overlay.names = make(map[*graphics.Cursor]string)
-	overlay.generator = rand.New(rand.NewSource(replayController.GetBeatMap().TimeAdded))
+	overlay.names = make(map[*graphics.CursorState]string)
+	overlay.generator = bmath.NewRandom(settings.SEED, "knockout")
 	//overlay.deaths = make(map[int64]int64)
 
 	for i, r := range replayController.GetReplays() {
@@ -125,7 +132,7 @@ func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverl
 	}
 
 	if settings.Knockout.LiveSort {
-		rand.Shuffle(len(overlay.playersArray), func(i, j int) {
+		overlay.generator.Shuffle(len(overlay.playersArray), func(i, j int) {
 			overlay.playersArray[i], overlay.playersArray[j] = overlay.playersArray[j], overlay.playersArray[i]
 		})
 	}
@@ -140,7 +147,7 @@ func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverl
 		}
 	}
 
-	replayController.GetRuleset().SetListener(func(cursor *graphics.Cursor, time int64, number int64, position vector.Vector2d, result osu.HitResult, comboResult osu.ComboResult, pp float64, score int64) {
+	replayController.GetRuleset().SetListener(func(cursor *graphics.CursorState, time int64, number int64, position vector.Vector2d, result osu.HitResult, comboResult osu.ComboResult, pp float64, score int64) {
 		player := overlay.players[overlay.names[cursor]]
 
 		player.score = score
@@ -168,7 +175,7 @@ func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverl
 			player.scaleHit.AddEventS(float64(time), float64(time+300), 0.5, 1)
 			player.lastHit = result & (osu.HitValues | osu.Miss) //resultClean
 			if settings.Knockout.Mode == settings.OneVsOne {
-				overlay.deathBubbles = append(overlay.deathBubbles, newBubble(position, time, overlay.names[cursor], player.sCombo, resultClean, comboResult))
+				overlay.deathBubbles = append(overlay.deathBubbles, newBubble(overlay.generator, position, time, overlay.names[cursor], player.sCombo, resultClean, comboResult))
 			}
 		}
 
@@ -177,7 +184,7 @@ func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverl
 			if !player.hasBroken {
 				if settings.Knockout.Mode == settings.XReplays {
 					if player.sCombo >= int64(settings.Knockout.BubbleMinimumCombo) {
-						overlay.deathBubbles = append(overlay.deathBubbles, newBubble(position, time, overlay.names[cursor], player.sCombo, resultClean, comboResult))
+						overlay.deathBubbles = append(overlay.deathBubbles, newBubble(overlay.generator, position, time, overlay.names[cursor], player.sCombo, resultClean, comboResult))
 						log.Println(overlay.names[cursor], "has broken! Combo:", player.sCombo)
 					}
 				} else if settings.Knockout.Mode == settings.ComboBreak || (settings.Knockout.Mode == settings.MaxCombo && math.Abs(float64(player.sCombo-player.maxCombo)) < 5) {
@@ -190,7 +197,9 @@ func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverl
 					player.height.SetEasing(easing.OutQuad)
 					player.height.AddEvent(float64(time+2500), float64(time+3000), 0)
 
-					overlay.deathBubbles = append(overlay.deathBubbles, newBubble(position, time, overlay.names[cursor], player.sCombo, resultClean, comboResult))
+					overlay.deathBubbles = append(overlay.deathBubbles, newBubble(overlay.generator, position, time, overlay.names[cursor], player.sCombo, resultClean, comboResult))
+
+					overlay.strainGraph.AddMarker(float64(time))
 
 					log.Println(overlay.names[cursor], "has broken! Max combo:", player.sCombo)
 				}
@@ -249,6 +258,15 @@ func NewKnockoutOverlay(replayController *dance.ReplayController) *KnockoutOverl
 
 	overlay.boundaries = common.NewBoundaries()
 
+	overlay.scaledHeight = 768
+	overlay.scaledWidth = settings.Graphics.GetAspectRatio() * overlay.scaledHeight
+
+	overlay.graphCamera = camera2.NewCamera()
+	overlay.graphCamera.SetViewportF(0, int(overlay.scaledHeight), int(overlay.scaledWidth), 0)
+	overlay.graphCamera.Update()
+
+	overlay.strainGraph = play.NewStrainGraph(replayController.GetBeatMap(), replayController.GetRuleset().GetStrains(replayController.GetCursors()[0]), int(overlay.scaledWidth/6))
+
 	return overlay
 }
 
@@ -351,6 +369,16 @@ func (overlay *KnockoutOverlay) DrawNormal(batch *batch.QuadBatch, colors []colo
 }
 
 func (overlay *KnockoutOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Color, alpha float64) {
+	if settings.Gameplay.ProgressBar == "StrainGraph" {
+		prev := batch.Projection
+		batch.SetCamera(overlay.graphCamera.GetProjectionView())
+
+		pos, size := strainBarBounds(overlay.scaledWidth, overlay.scaledHeight)
+		overlay.strainGraph.Draw(batch, pos, size, float64(overlay.lastTime), alpha*0.6)
+
+		batch.SetCamera(prev)
+	}
+
 	controller := overlay.controller
 	replays := controller.GetReplays()
 
@@ -500,7 +528,7 @@ func (overlay *KnockoutOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.
 	}
 }
 
-func (overlay *KnockoutOverlay) IsBroken(cursor *graphics.Cursor) bool {
+func (overlay *KnockoutOverlay) IsBroken(cursor *graphics.CursorState) bool {
 	return overlay.players[overlay.names[cursor]].hasBroken
 }
 
@@ -524,3 +552,5 @@ func humanize(number int64) string {
 
 	return humanized
 }
+
+func (overlay *KnockoutOverlay) ReloadTextures() {}
@@ -0,0 +1,151 @@
+package play
+
+import (
+	"github.com/wieku/danser-go/app/audio"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/app/skin"
+	"github.com/wieku/danser-go/framework/bass"
+	"github.com/wieku/danser-go/framework/graphics/batch"
+	"github.com/wieku/danser-go/framework/graphics/sprite"
+	"github.com/wieku/danser-go/framework/graphics/texture"
+	"github.com/wieku/danser-go/framework/math/animation"
+	"github.com/wieku/danser-go/framework/math/animation/easing"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math/rand"
+	"strconv"
+)
+
+const (
+	burstSlideIn = 700.0
+	burstStay    = 300.0
+	burstFadeOut = 400.0
+)
+
+// ComboBurst shows skin's comboburst images and plays comboburst sounds at combo milestones.
+type ComboBurst struct {
+	manager *sprite.SpriteManager
+	random  *rand.Rand
+
+	images []*texture.TextureRegion
+	sounds []*bass.Sample
+
+	imageIndex int
+	soundIndex int
+	rightSide  bool
+
+	lastTime int64
+
+	width  float64
+	height float64
+}
+
+func NewComboBurst(width, height float64) *ComboBurst {
+	burst := &ComboBurst{
+		manager: sprite.NewSpriteManager(),
+		random:  bmath.NewRandom(settings.SEED, "comboburst"),
+		images:  skin.GetFrames("comboburst", true),
+		width:   width,
+		height:  height,
+	}
+
+	for i := 0; ; i++ {
+		sample := audio.LoadSample("comboburst-" + strconv.Itoa(i))
+		if sample == nil {
+			break
+		}
+
+		burst.sounds = append(burst.sounds, sample)
+	}
+
+	if len(burst.sounds) == 0 {
+		if sample := audio.LoadSample("comboburst"); sample != nil {
+			burst.sounds = append(burst.sounds, sample)
+		}
+	}
+
+	return burst
+}
+
+// isMilestone returns true for combos where osu! shows a burst: 30, 60, 100 and every 100 after that.
+func isMilestone(combo int64) bool {
+	return combo == 30 || combo == 60 || (combo >= 100 && combo%100 == 0)
+}
+
+func (burst *ComboBurst) isSoundMilestone(combo int64) bool {
+	customSounds := skin.GetInfo().CustomComboBurstSounds
+	if len(customSounds) == 0 {
+		return isMilestone(combo)
+	}
+
+	for _, c := range customSounds {
+		if c == combo {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Add should be called with the new combo value every time it increases.
+func (burst *ComboBurst) Add(time int64, combo int64) {
+	if burst.isSoundMilestone(combo) && len(burst.sounds) > 0 {
+		burst.sounds[burst.next(&burst.soundIndex, len(burst.sounds))].Play()
+	}
+
+	if !isMilestone(combo) || len(burst.images) == 0 {
+		return
+	}
+
+	image := burst.images[burst.next(&burst.imageIndex, len(burst.images))]
+
+	startX, endX := -float64(image.Width), 0.0
+	origin := bmath.Origin.BottomLeft
+
+	if burst.rightSide {
+		startX, endX = burst.width+float64(image.Width), burst.width
+		origin = bmath.Origin.BottomRight
+	}
+
+	startTime := float64(time)
+	fadeStart := startTime + burstSlideIn + burstStay
+
+	burstSprite := sprite.NewSpriteSingle(image, startTime, vector.NewVec2d(startX, burst.height), origin)
+	burstSprite.SetHFlip(burst.rightSide)
+	burstSprite.ShowForever(false)
+	burstSprite.AddTransform(animation.NewSingleTransform(animation.MoveX, easing.OutQuad, startTime, startTime+burstSlideIn, startX, endX))
+	burstSprite.AddTransform(animation.NewSingleTransform(animation.Fade, easing.Linear, fadeStart, fadeStart+burstFadeOut, 1.0, 0.0))
+	burstSprite.SortTransformations()
+	burstSprite.AdjustTimesToTransformations()
+	burstSprite.ResetValuesToTransforms()
+
+	burst.manager.Add(burstSprite)
+
+	burst.rightSide = !burst.rightSide
+}
+
+// next picks the next image or sound, randomly if skin asks for it
+func (burst *ComboBurst) next(index *int, count int) int {
+	if skin.GetInfo().ComboBurstRandom {
+		return burst.random.Intn(count)
+	}
+
+	current := *index % count
+	*index++
+
+	return current
+}
+
+func (burst *ComboBurst) Update(time int64) {
+	burst.manager.Update(time)
+	burst.lastTime = time
+}
+
+func (burst *ComboBurst) Draw(batch *batch.QuadBatch, alpha float64) {
+	batch.ResetTransform()
+	batch.SetColor(1, 1, 1, alpha)
+
+	burst.manager.Draw(burst.lastTime, batch)
+
+	batch.ResetTransform()
+}
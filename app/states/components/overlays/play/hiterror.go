@@ -129,6 +129,14 @@ func (meter *HitErrorMeter) Add(time, error float64) {
 	meter.errorDisplayFade.AddEventSEase(time+4000, time+5000, 1.0, 0.0, easing.InQuad)
 }
 
+// GetBounds returns top-left corner and size of the area occupied by the meter
+func (meter *HitErrorMeter) GetBounds() (vector.Vector2d, vector.Vector2d) {
+	scale := settings.Gameplay.HitErrorMeter.Scale * errorBaseScale
+	width := float64(meter.diff.Hit50) * 2 * scale
+
+	return vector.NewVec2d(meter.Width/2-width/2, meter.Height-20*scale), vector.NewVec2d(width, 20*scale)
+}
+
 func (meter *HitErrorMeter) Update(time float64) {
 	meter.errorDisplayFade.Update(time)
 	meter.errorDisplay.Update(int64(time))
@@ -4,6 +4,7 @@ import (
 	"github.com/wieku/danser-go/app/beatmap/difficulty"
 	"github.com/wieku/danser-go/app/bmath"
 	"github.com/wieku/danser-go/app/rulesets/osu"
+	"github.com/wieku/danser-go/app/settings"
 	"github.com/wieku/danser-go/app/skin"
 	"github.com/wieku/danser-go/framework/graphics/batch"
 	"github.com/wieku/danser-go/framework/graphics/sprite"
@@ -18,6 +19,7 @@ type HitResults struct {
 	manager  *sprite.SpriteManager
 	lastTime float64
 	diff     *difficulty.Difficulty
+	random   *rand.Rand
 }
 
 func NewHitResults(diff *difficulty.Difficulty) *HitResults {
@@ -30,7 +32,7 @@ func NewHitResults(diff *difficulty.Difficulty) *HitResults {
 	skin.GetFrames("hit300k", true)
 	skin.GetFrames("hit300g", true)
 
-	return &HitResults{manager: sprite.NewSpriteManager(), diff: diff}
+	return &HitResults{manager: sprite.NewSpriteManager(), diff: diff, random: bmath.NewRandom(settings.SEED, "hitresults")}
 }
 
 func (results *HitResults) AddResult(time int64, result osu.HitResult, position vector.Vector2d) {
@@ -73,8 +75,8 @@ func (results *HitResults) AddResult(time int64, result osu.HitResult, position
 			particles = true
 
 			for i := 0; i < 150; i++ {
-				fadeOut := 500 + 700*rand.Float64()
-				direction := vector.NewVec2dRad(rand.Float64()*2*math.Pi, rand.Float64()*35)
+				fadeOut := 500 + 700*results.random.Float64()
+				direction := vector.NewVec2dRad(results.random.Float64()*2*math.Pi, results.random.Float64()*35)
 
 				sp := sprite.NewSpriteSingle(particleTex, float64(time), position, bmath.Origin.Centre)
 				sp.SetAdditive(true)
@@ -112,7 +114,7 @@ func (results *HitResults) AddResult(time int64, result osu.HitResult, position
 		}
 
 		if result == osu.Miss {
-			rotation := rand.Float64()*0.3 - 0.15
+			rotation := results.random.Float64()*0.3 - 0.15
 
 			sprite.AddTransformUnordered(animation.NewSingleTransform(animation.Rotate, easing.Linear, float64(time), fadeIn, 0.0, rotation))
 			sprite.AddTransformUnordered(animation.NewSingleTransform(animation.Rotate, easing.Linear, fadeIn, fadeOut, rotation, rotation*2))
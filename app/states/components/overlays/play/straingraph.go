@@ -0,0 +1,179 @@
+package play
+
+import (
+	"github.com/wieku/danser-go/app/beatmap"
+	"github.com/wieku/danser-go/app/bmath"
+	"github.com/wieku/danser-go/app/graphics"
+	"github.com/wieku/danser-go/app/rulesets/osu"
+	"github.com/wieku/danser-go/framework/graphics/batch"
+	color2 "github.com/wieku/danser-go/framework/math/color"
+	"github.com/wieku/danser-go/framework/math/vector"
+	"math"
+)
+
+var (
+	aimColor    = color2.Color{R: 1, G: 0.55, B: 0.3, A: 1}
+	speedColor  = color2.Color{R: 0.3, G: 0.65, B: 1, A: 1}
+	kiaiColor   = color2.Color{R: 1, G: 0.8, B: 0.2, A: 0.25}
+	breakColor  = color2.Color{R: 0.5, G: 0.5, B: 0.5, A: 0.25}
+	markerColor = color2.Color{R: 1, G: 0.2, B: 0.2, A: 1}
+)
+
+type timeRange struct {
+	start, end float64
+}
+
+// StrainGraph draws map's aim and speed strains binned into sections, sections already played are highlighted.
+// Breaks, kiai sections and custom markers (like knockouts) are shown on the graph as well.
+type StrainGraph struct {
+	startTime float64
+	endTime   float64
+
+	// values are normalized so the highest section has aim+speed equal to 1
+	aim   []float64
+	speed []float64
+
+	breaks  []timeRange
+	kiai    []timeRange
+	markers []float64
+}
+
+func NewStrainGraph(beatMap *beatmap.BeatMap, strains []osu.StrainPoint, sections int) *StrainGraph {
+	graph := &StrainGraph{
+		aim:   make([]float64, sections),
+		speed: make([]float64, sections),
+	}
+
+	hObjects := beatMap.HitObjects
+
+	graph.startTime = float64(hObjects[0].GetBasicData().StartTime)
+	graph.endTime = float64(hObjects[len(hObjects)-1].GetBasicData().EndTime)
+
+	length := math.Max(1, graph.endTime-graph.startTime)
+
+	for _, point := range strains {
+		section := bmath.ClampI(int((point.Time-graph.startTime)/length*float64(sections)), 0, sections-1)
+
+		graph.aim[section] = math.Max(graph.aim[section], point.Aim)
+		graph.speed[section] = math.Max(graph.speed[section], point.Speed)
+	}
+
+	maxStrain := 0.0
+	for i := range graph.aim {
+		maxStrain = math.Max(maxStrain, graph.aim[i]+graph.speed[i])
+	}
+
+	if maxStrain > 0 {
+		for i := range graph.aim {
+			graph.aim[i] /= maxStrain
+			graph.speed[i] /= maxStrain
+		}
+	}
+
+	for _, p := range beatMap.Pauses {
+		bd := p.GetBasicData()
+		graph.breaks = append(graph.breaks, timeRange{float64(bd.StartTime), float64(bd.EndTime)})
+	}
+
+	kiaiStart := math.NaN()
+
+	for _, point := range beatMap.Timings.Points {
+		if point.Kiai && math.IsNaN(kiaiStart) {
+			kiaiStart = float64(point.Time)
+		} else if !point.Kiai && !math.IsNaN(kiaiStart) {
+			graph.kiai = append(graph.kiai, timeRange{kiaiStart, float64(point.Time)})
+			kiaiStart = math.NaN()
+		}
+	}
+
+	if !math.IsNaN(kiaiStart) {
+		graph.kiai = append(graph.kiai, timeRange{kiaiStart, graph.endTime})
+	}
+
+	return graph
+}
+
+// AddMarker adds a line on the graph at given time
+func (graph *StrainGraph) AddMarker(time float64) {
+	graph.markers = append(graph.markers, time)
+}
+
+// GetProgress returns how much of the map has been played at given time, in 0-1 range
+func (graph *StrainGraph) GetProgress(time float64) float64 {
+	return bmath.ClampF64((time-graph.startTime)/math.Max(1, graph.endTime-graph.startTime), 0, 1)
+}
+
+// Draw draws the graph inside the given rectangle, pos being its top-left corner
+func (graph *StrainGraph) Draw(batch *batch.QuadBatch, pos, size vector.Vector2d, time, alpha float64) {
+	sections := len(graph.aim)
+	sectionWidth := size.X / float64(sections)
+	progress := graph.GetProgress(time) * float64(sections)
+
+	pixel := graphics.Pixel.GetRegion()
+
+	batch.ResetTransform()
+
+	for _, r := range graph.kiai {
+		graph.drawRange(batch, pos, size, r, kiaiColor, alpha)
+	}
+
+	for _, r := range graph.breaks {
+		graph.drawRange(batch, pos, size, r, breakColor, alpha)
+	}
+
+	for i := 0; i < sections; i++ {
+		sAlpha := 0.3
+		if float64(i) < progress {
+			sAlpha = 1.0
+		}
+
+		x := pos.X + (float64(i)+0.5)*sectionWidth
+		speedHeight := graph.speed[i] * size.Y
+		aimHeight := graph.aim[i] * size.Y
+
+		batch.SetColor(float64(speedColor.R), float64(speedColor.G), float64(speedColor.B), alpha*sAlpha)
+		batch.SetSubScale(sectionWidth/2, speedHeight/2)
+		batch.SetTranslation(vector.NewVec2d(x, pos.Y+size.Y-speedHeight/2))
+		batch.DrawUnit(pixel)
+
+		batch.SetColor(float64(aimColor.R), float64(aimColor.G), float64(aimColor.B), alpha*sAlpha)
+		batch.SetSubScale(sectionWidth/2, aimHeight/2)
+		batch.SetTranslation(vector.NewVec2d(x, pos.Y+size.Y-speedHeight-aimHeight/2))
+		batch.DrawUnit(pixel)
+	}
+
+	for _, marker := range graph.markers {
+		if marker <= time {
+			graph.drawLine(batch, pos, size, marker, 2, markerColor, alpha)
+		}
+	}
+
+	graph.drawLine(batch, pos, size, time, 1, color2.Color{R: 1, G: 1, B: 1, A: 0.8}, alpha)
+
+	batch.ResetTransform()
+}
+
+func (graph *StrainGraph) timeToX(pos, size vector.Vector2d, time float64) float64 {
+	return pos.X + graph.GetProgress(time)*size.X
+}
+
+func (graph *StrainGraph) drawRange(batch *batch.QuadBatch, pos, size vector.Vector2d, r timeRange, color color2.Color, alpha float64) {
+	x1 := graph.timeToX(pos, size, r.start)
+	x2 := graph.timeToX(pos, size, r.end)
+
+	if x2-x1 < 0.01 {
+		return
+	}
+
+	batch.SetColor(float64(color.R), float64(color.G), float64(color.B), float64(color.A)*alpha)
+	batch.SetSubScale((x2-x1)/2, size.Y/2)
+	batch.SetTranslation(vector.NewVec2d((x1+x2)/2, pos.Y+size.Y/2))
+	batch.DrawUnit(graphics.Pixel.GetRegion())
+}
+
+func (graph *StrainGraph) drawLine(batch *batch.QuadBatch, pos, size vector.Vector2d, time, width float64, color color2.Color, alpha float64) {
+	batch.SetColor(float64(color.R), float64(color.G), float64(color.B), float64(color.A)*alpha)
+	batch.SetSubScale(width/2, size.Y/2)
+	batch.SetTranslation(vector.NewVec2d(graph.timeToX(pos, size, time), pos.Y+size.Y/2))
+	batch.DrawUnit(graphics.Pixel.GetRegion())
+}
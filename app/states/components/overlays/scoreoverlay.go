@@ -3,6 +3,7 @@ package overlays
 import (
 	"fmt"
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
 	"github.com/wieku/danser-go/app/audio"
 	"github.com/wieku/danser-go/app/beatmap/objects"
 	"github.com/wieku/danser-go/app/bmath"
@@ -20,6 +21,7 @@ import (
 	"github.com/wieku/danser-go/framework/graphics/batch"
 	"github.com/wieku/danser-go/framework/graphics/shape"
 	"github.com/wieku/danser-go/framework/graphics/sprite"
+	"github.com/wieku/danser-go/framework/graphics/texture"
 	"github.com/wieku/danser-go/framework/math/animation"
 	"github.com/wieku/danser-go/framework/math/animation/easing"
 	color2 "github.com/wieku/danser-go/framework/math/color"
@@ -34,8 +36,9 @@ type Overlay interface {
 	DrawBeforeObjects(batch *batch.QuadBatch, colors []color2.Color, alpha float64)
 	DrawNormal(batch *batch.QuadBatch, colors []color2.Color, alpha float64)
 	DrawHUD(batch *batch.QuadBatch, colors []color2.Color, alpha float64)
-	IsBroken(cursor *graphics.Cursor) bool
+	IsBroken(cursor *graphics.CursorState) bool
 	NormalBeforeCursor() bool
+	ReloadTextures()
 }
 
 type ScoreOverlay struct {
@@ -54,11 +57,12 @@ type ScoreOverlay struct {
 	scoreGlider *animation.Glider
 	ppGlider    *animation.Glider
 	ruleset     *osu.OsuRuleSet
-	cursor      *graphics.Cursor
+	cursor      *graphics.CursorState
 	combobreak  *bass.Sample
 	music       *bass.Track
 	nextEnd     int64
 	results     *play.HitResults
+	comboBurst  *play.ComboBurst
 
 	keyStates   [4]bool
 	keyCounters [4]int
@@ -76,21 +80,32 @@ type ScoreOverlay struct {
 	bgDim *animation.Glider
 
 	hitErrorMeter *play.HitErrorMeter
+	strainGraph   *play.StrainGraph
+	progressGraph *play.StrainGraph
 
 	skip *sprite.Sprite
 
 	healthBackground *sprite.Sprite
 	healthBar        *sprite.Sprite
+	healthBgWidth    float64
+	healthBgHeight   float64
+	healthBarWidth   float64
+	healthBarHeight  float64
 	displayHp        float64
 
+	// v2 skins use scorebar-marker, older ones scorebar-ki textures that change when health is low
+	healthMarker  *texture.TextureRegion
+	healthKi      *texture.TextureRegion
+	healthDanger  *texture.TextureRegion
+	healthDanger2 *texture.TextureRegion
+
 	shapeRenderer *shape.Renderer
 
 	boundaries *common.Boundaries
 }
 
-func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOverlay {
+func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.CursorState) *ScoreOverlay {
 	overlay := new(ScoreOverlay)
-	overlay.results = play.NewHitResults(ruleset.GetBeatMap().Diff)
 	overlay.ruleset = ruleset
 	overlay.cursor = cursor
 	overlay.font = font.GetFont("Exo 2 Bold")
@@ -110,8 +125,6 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 
 	overlay.bgDim = animation.NewGlider(1)
 
-	overlay.combobreak = audio.LoadSample("combobreak")
-
 	for _, p := range ruleset.GetBeatMap().Pauses {
 		bd := p.GetBasicData()
 
@@ -128,11 +141,7 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 
 	discord.UpdatePlay(cursor.Name)
 
-	overlay.scoreEFont = skin.GetFont("scoreentry")
-	overlay.scoreFont = skin.GetFont("score")
-	overlay.comboFont = skin.GetFont("combo")
-
-	ruleset.SetListener(func(cursor *graphics.Cursor, time int64, number int64, position vector.Vector2d, result osu.HitResult, comboResult osu.ComboResult, pp float64, score1 int64) {
+	ruleset.SetListener(func(cursor *graphics.CursorState, time int64, number int64, position vector.Vector2d, result osu.HitResult, comboResult osu.ComboResult, pp float64, score1 int64) {
 
 		if result&(osu.BaseHitsM) > 0 {
 			overlay.results.AddResult(time, result, position)
@@ -161,6 +170,8 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 			overlay.combo = overlay.newCombo
 			overlay.newCombo++
 			overlay.nextEnd = time + 300
+
+			overlay.comboBurst.Add(time, overlay.newCombo)
 		} else if comboResult == osu.ComboResults.Reset {
 			if overlay.newCombo > 20 {
 				overlay.combobreak.Play()
@@ -185,7 +196,31 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 	overlay.camera.SetViewportF(0, int(overlay.ScaledHeight), int(overlay.ScaledWidth), 0)
 	overlay.camera.Update()
 
+	overlay.hitErrorMeter = play.NewHitErrorMeter(overlay.ScaledWidth, overlay.ScaledHeight, ruleset.GetBeatMap().Diff)
+	overlay.strainGraph = play.NewStrainGraph(ruleset.GetBeatMap(), ruleset.GetStrains(cursor), 100)
+	overlay.progressGraph = play.NewStrainGraph(ruleset.GetBeatMap(), ruleset.GetStrains(cursor), int(overlay.ScaledWidth/6))
+
+	overlay.loadSkinElements()
+
+	overlay.shapeRenderer = shape.NewRenderer()
+
+	overlay.boundaries = common.NewBoundaries()
+
+	return overlay
+}
+
+// loadSkinElements creates all parts of the overlay that use skin's textures
+func (overlay *ScoreOverlay) loadSkinElements() {
+	overlay.results = play.NewHitResults(overlay.ruleset.GetBeatMap().Diff)
+
+	overlay.scoreEFont = skin.GetFont("scoreentry")
+	overlay.scoreFont = skin.GetFont("score")
+	overlay.comboFont = skin.GetFont("combo")
+
+	overlay.combobreak = audio.LoadSample("combobreak")
+
 	overlay.keyOverlay = sprite.NewSpriteManager()
+	overlay.keys = nil
 
 	keyBg := sprite.NewSpriteSingle(skin.GetTexture("inputoverlay-background"), 0, vector.NewVec2d(overlay.ScaledWidth, overlay.ScaledHeight/2-64), bmath.Origin.TopLeft)
 	keyBg.SetScaleV(vector.NewVec2d(1.05, 1))
@@ -204,11 +239,9 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 		overlay.keyOverlay.Add(key)
 	}
 
-	overlay.hitErrorMeter = play.NewHitErrorMeter(overlay.ScaledWidth, overlay.ScaledHeight, ruleset.GetBeatMap().Diff)
-
-	start := overlay.ruleset.GetBeatMap().HitObjects[0].GetBasicData().StartTime - 2000
+	overlay.comboBurst = play.NewComboBurst(overlay.ScaledWidth, overlay.ScaledHeight)
 
-	if start > 2000 {
+	if start := overlay.ruleset.GetBeatMap().HitObjects[0].GetBasicData().StartTime - 2000; start > 2000 {
 		skipFrames := skin.GetFrames("play-skip", true)
 		overlay.skip = sprite.NewAnimation(skipFrames, skin.GetInfo().GetFrameTime(len(skipFrames)), true, 0.0, vector.NewVec2d(overlay.ScaledWidth, overlay.ScaledHeight), bmath.Origin.BottomRight)
 		overlay.skip.SetAlpha(0.0)
@@ -216,23 +249,43 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 		overlay.skip.AddTransform(animation.NewSingleTransform(animation.Fade, easing.OutQuad, float64(start), float64(start+300), 0.6, 0.0))
 	}
 
-	overlay.healthBackground = sprite.NewSpriteSingle(skin.GetTexture("scorebar-bg"), 0, vector.NewVec2d(0, 0), bmath.Origin.TopLeft)
+	healthBg := skin.GetTexture("scorebar-bg")
+
+	overlay.healthBackground = sprite.NewSpriteSingle(healthBg, 0, vector.NewVec2d(0, 0), bmath.Origin.TopLeft)
 
 	pos := vector.NewVec2d(4.8, 16)
-	if skin.GetTexture("scorebar-marker") != nil {
+
+	overlay.healthMarker, overlay.healthKi, overlay.healthDanger, overlay.healthDanger2 = nil, nil, nil, nil
+
+	if marker := skin.GetTexture("scorebar-marker"); marker != nil && skin.GetInfo().Version >= 2 {
 		pos = vector.NewVec2d(12, 12.5)
+		overlay.healthMarker = marker
+	} else {
+		overlay.healthKi = skin.GetTexture("scorebar-ki")
+		overlay.healthDanger = skin.GetTexture("scorebar-kidanger")
+		overlay.healthDanger2 = skin.GetTexture("scorebar-kidanger2")
 	}
 
 	barTextures := skin.GetFrames("scorebar-colour", true)
+	if len(barTextures) > 0 {
+		overlay.healthBarWidth = float64(barTextures[0].Width)
+		overlay.healthBarHeight = float64(barTextures[0].Height)
+	}
+
+	overlay.healthBgWidth, overlay.healthBgHeight = overlay.healthBarWidth, overlay.healthBarHeight
+	if healthBg != nil {
+		overlay.healthBgWidth = float64(healthBg.Width)
+		overlay.healthBgHeight = float64(healthBg.Height)
+	}
 
 	overlay.healthBar = sprite.NewAnimation(barTextures, skin.GetInfo().GetFrameTime(len(barTextures)), true, 0.0, pos, bmath.Origin.TopLeft)
 	overlay.healthBar.SetCutOrigin(bmath.Origin.CentreLeft)
+}
 
-	overlay.shapeRenderer = shape.NewRenderer()
-
-	overlay.boundaries = common.NewBoundaries()
-
-	return overlay
+// ReloadTextures recreates skin elements after the skin has changed, gameplay state is kept.
+func (overlay *ScoreOverlay) ReloadTextures() {
+	overlay.loadSkinElements()
+	overlay.Update(overlay.lastTime)
 }
 
 func (overlay *ScoreOverlay) animate(time int64) {
@@ -287,6 +340,8 @@ func (overlay *ScoreOverlay) Update(time int64) {
 
 	overlay.hitErrorMeter.Update(float64(time))
 
+	overlay.comboBurst.Update(time)
+
 	currentStates := [4]bool{overlay.cursor.LeftKey, overlay.cursor.RightKey, overlay.cursor.LeftMouse && !overlay.cursor.LeftKey, overlay.cursor.RightMouse && !overlay.cursor.RightKey}
 
 	for i, state := range currentStates {
@@ -344,10 +399,16 @@ func (overlay *ScoreOverlay) DrawNormal(batch *batch.QuadBatch, colors []color2.
 	overlay.results.Draw(batch, 1.0)
 
 	prev := batch.Projection
-	batch.SetCamera(overlay.camera.GetProjectionView())
+
+	errPos, errSize := overlay.hitErrorMeter.GetBounds()
+	errLayout := newHudLayout(bmath.Origin.BottomCentre, errPos.X, errPos.Y, errSize.X, errSize.Y)
+	errSettings := settings.Gameplay.HitErrorMeter
+
+	batch.SetCamera(overlay.layoutCamera(errLayout, errSettings.Anchor, errSettings.XOffset, errSettings.YOffset, errSettings.Rotation))
 
 	overlay.hitErrorMeter.Draw(batch, alpha)
 
+	batch.SetCamera(overlay.camera.GetProjectionView())
 	batch.SetScale(1, 1)
 	batch.SetColor(1, 1, 1, alpha)
 
@@ -378,15 +439,22 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Col
 		progress = bmath.ClampF32(-1.0+musicPos/startTime, -1.0, 0.0)
 	}
 
-	if scoreAlpha := settings.Gameplay.Score.Opacity; scoreAlpha > 0.001 && settings.Gameplay.Score.Show && settings.Gameplay.ProgressBar == "Pie" {
-		scoreScale := settings.Gameplay.Score.Scale
-		fntSize := overlay.scoreFont.GetSize() * scoreScale * 0.96
+	scoreSettings := settings.Gameplay.Score
+
+	scoreScale := scoreSettings.Scale
+	fntSize := overlay.scoreFont.GetSize() * scoreScale * 0.96
+	rightOffset := -9.6
+	vAccOffset := 4.8
 
-		rightOffset := -9.6
+	scoreWidth := overlay.scoreFont.GetWidthMonospaced(fntSize, "00000000") - rightOffset
+	scoreHeight := fntSize + vAccOffset + fntSize*0.6
+	scoreLayout := newHudLayout(bmath.Origin.TopRight, overlay.ScaledWidth-scoreWidth, 0, scoreWidth, scoreHeight)
+	scoreCamera := overlay.layoutCamera(scoreLayout, scoreSettings.Anchor, scoreSettings.XOffset, scoreSettings.YOffset, scoreSettings.Rotation)
+
+	if scoreAlpha := scoreSettings.Opacity; scoreAlpha > 0.001 && scoreSettings.Show && settings.Gameplay.ProgressBar == "Pie" {
 		accOffset := overlay.ScaledWidth - overlay.scoreFont.GetWidthMonospaced(fntSize*0.6, "99.99%") - 38.4 + rightOffset
-		vAccOffset := 4.8
 
-		overlay.shapeRenderer.SetCamera(overlay.camera.GetProjectionView())
+		overlay.shapeRenderer.SetCamera(scoreCamera)
 
 		if progress < 0.0 {
 			overlay.shapeRenderer.SetColor(0.4, 0.8, 0.4, alpha*0.6*scoreAlpha)
@@ -399,13 +467,49 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Col
 		overlay.shapeRenderer.End()
 	}
 
-	overlay.healthBackground.Draw(overlay.lastTime, batch)
-	overlay.healthBar.Draw(overlay.lastTime, batch)
+	if settings.Gameplay.ProgressBar == "StrainGraph" {
+		pos, size := strainBarBounds(overlay.ScaledWidth, overlay.ScaledHeight)
+		overlay.progressGraph.Draw(batch, pos, size, float64(overlay.lastTime), alpha*0.6)
+	}
+
+	overlay.comboBurst.Draw(batch, alpha)
+
+	if hpSettings := settings.Gameplay.HPBar; hpSettings.Opacity > 0.001 && hpSettings.Show {
+		hpScale := hpSettings.Scale
+
+		// bar is drawn in its unscaled coordinates, scaling is done around the top-left corner
+		hpLayout := newHudLayout(bmath.Origin.TopLeft, 0, 0, overlay.healthBgWidth*hpScale, overlay.healthBgHeight*hpScale)
+		hpCamera := overlay.layoutCamera(hpLayout, hpSettings.Anchor, hpSettings.XOffset, hpSettings.YOffset, hpSettings.Rotation)
+
+		batch.SetCamera(hpCamera.Mul4(mgl32.Scale3D(float32(hpScale), float32(hpScale), 1)))
+		batch.SetColor(1, 1, 1, alpha*hpSettings.Opacity)
+
+		overlay.healthBackground.Draw(overlay.lastTime, batch)
+		overlay.healthBar.Draw(overlay.lastTime, batch)
+
+		if marker := overlay.getHealthMarker(); marker != nil {
+			barPos := overlay.healthBar.GetPosition()
+
+			batch.SetTranslation(vector.NewVec2d(barPos.X+overlay.healthBarWidth*overlay.displayHp, barPos.Y+overlay.healthBarHeight/2))
+			batch.SetAdditive(overlay.healthMarker != nil)
+			batch.DrawTexture(*marker)
+			batch.SetAdditive(false)
+			batch.ResetTransform()
+		}
+
+		batch.SetCamera(overlay.camera.GetProjectionView())
+	}
+
+	batch.SetColor(1, 1, 1, alpha)
 
 	//region Combo rendering
 
-	if comboAlpha := settings.Gameplay.ComboCounter.Opacity; comboAlpha > 0.001 && settings.Gameplay.ComboCounter.Show {
-		cmbSize := overlay.comboFont.GetSize() * settings.Gameplay.ComboCounter.Scale
+	if comboSettings := settings.Gameplay.ComboCounter; comboSettings.Opacity > 0.001 && comboSettings.Show {
+		comboAlpha := comboSettings.Opacity
+		cmbSize := overlay.comboFont.GetSize() * comboSettings.Scale
+
+		comboLayout := newHudLayout(bmath.Origin.BottomLeft, 0, overlay.ScaledHeight-cmbSize, overlay.comboFont.GetWidth(cmbSize, fmt.Sprintf("%dx", overlay.combo)), cmbSize)
+		batch.SetCamera(overlay.layoutCamera(comboLayout, comboSettings.Anchor, comboSettings.XOffset, comboSettings.YOffset, comboSettings.Rotation))
 
 		batch.SetColor(1, 1, 1, overlay.newComboFadeB.GetValue()*alpha*comboAlpha)
 
@@ -420,14 +524,11 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Col
 
 	//region Score+progress+accuracy
 
-	if scoreAlpha := settings.Gameplay.Score.Opacity; scoreAlpha > 0.001 && settings.Gameplay.Score.Show {
+	if scoreAlpha := scoreSettings.Opacity; scoreAlpha > 0.001 && scoreSettings.Show {
+		batch.SetCamera(scoreCamera)
 		batch.ResetTransform()
 
-		scoreScale := settings.Gameplay.Score.Scale
-		fntSize := overlay.scoreFont.GetSize() * scoreScale * 0.96
-		rightOffset := -9.6
 		accOffset := overlay.ScaledWidth - overlay.scoreFont.GetWidthMonospaced(fntSize*0.6, "99.99%") - 38.4 + rightOffset
-		vAccOffset := 4.8
 
 		if settings.Gameplay.ProgressBar == "Pie" {
 			text := skin.GetTextureSource("circularmetre", skin.LOCAL)
@@ -437,7 +538,7 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Col
 			batch.DrawTexture(*text)
 
 			accOffset -= 44.8
-		} else if progress > 0.0 {
+		} else if progress > 0.0 && settings.Gameplay.ProgressBar != "StrainGraph" {
 			batch.SetColor(0.2, 0.6, 0.2, alpha*0.8*scoreAlpha)
 
 			batch.SetSubScale(272*float64(progress)*scoreScale/2, 2.5*scoreScale)
@@ -453,12 +554,7 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Col
 
 		acc, _, _, _ := overlay.ruleset.GetResults(overlay.cursor)
 
-		var accText string
-		if acc == 100 {
-			accText = fmt.Sprintf("%5.1f%%", acc)
-		} else {
-			accText = fmt.Sprintf("%5.2f%%", acc)
-		}
+		accText := formatAccuracy(acc)
 
 		overlay.scoreFont.DrawMonospaced(batch, overlay.ScaledWidth+rightOffset-overlay.scoreFont.GetWidthMonospaced(fntSize*0.6, accText)+skin.GetInfo().ScoreOverlap*0.6, fntSize+vAccOffset+fntSize*0.6/2, fntSize*0.6, accText)
 
@@ -477,22 +573,46 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Col
 
 	//endregion
 
-	//region pp
+	batch.ResetTransform()
 
-	batch.SetColor(1, 1, 1, alpha)
-	batch.SetScale(1, -1)
-	batch.SetSubScale(1, 1)
+	//region pp, unstable rate, accuracy if FC
 
-	overlay.font.DrawMonospaced(batch, 0, 150, 40, fmt.Sprintf("%0.2fpp", overlay.ppGlider.GetValue()))
+	if ppSettings := settings.Gameplay.PPCounter; ppSettings.Opacity > 0.001 && ppSettings.Show {
+		overlay.drawText(batch, ppSettings.Anchor, ppSettings.XOffset, ppSettings.YOffset, ppSettings.Rotation, 150, 40*ppSettings.Scale, alpha*ppSettings.Opacity, fmt.Sprintf("%0.2fpp", overlay.ppGlider.GetValue()))
+	}
 
-	batch.SetScale(1, 1)
+	if urSettings := settings.Gameplay.UnstableRate; urSettings.Opacity > 0.001 && urSettings.Show {
+		overlay.drawText(batch, urSettings.Anchor, urSettings.XOffset, urSettings.YOffset, urSettings.Rotation, 200, 20*urSettings.Scale, alpha*urSettings.Opacity, fmt.Sprintf("%0.2f UR", overlay.ruleset.GetUnstableRate(overlay.cursor)))
+	}
+
+	if fcSettings := settings.Gameplay.AccuracyIfFC; fcSettings.Opacity > 0.001 && fcSettings.Show {
+		overlay.drawText(batch, fcSettings.Anchor, fcSettings.XOffset, fcSettings.YOffset, fcSettings.Rotation, 225, 20*fcSettings.Scale, alpha*fcSettings.Opacity, "FC: "+formatAccuracy(overlay.ruleset.GetFCAccuracy(overlay.cursor)))
+	}
 
 	//endregion
 
+	if hitSettings := settings.Gameplay.HitCounter; hitSettings.Opacity > 0.001 && hitSettings.Show {
+		overlay.drawHitCounter(batch, alpha*hitSettings.Opacity)
+	}
+
+	if graphSettings := settings.Gameplay.StrainGraph; graphSettings.Opacity > 0.001 && graphSettings.Show {
+		size := vector.NewVec2d(300, 60).Scl(graphSettings.Scale)
+		pos := vector.NewVec2d(overlay.ScaledWidth-size.X-12, overlay.ScaledHeight-size.Y-12)
+
+		graphLayout := newHudLayout(bmath.Origin.BottomRight, pos.X, pos.Y, size.X, size.Y)
+		batch.SetCamera(overlay.layoutCamera(graphLayout, graphSettings.Anchor, graphSettings.XOffset, graphSettings.YOffset, graphSettings.Rotation))
+
+		overlay.strainGraph.Draw(batch, pos, size, float64(overlay.lastTime), alpha*graphSettings.Opacity)
+	}
+
 	batch.ResetTransform()
 
-	if keyAlpha := settings.Gameplay.KeyOverlay.Opacity; keyAlpha > 0.001 && settings.Gameplay.KeyOverlay.Show {
-		keyScale := settings.Gameplay.KeyOverlay.Scale
+	if keySettings := settings.Gameplay.KeyOverlay; keySettings.Opacity > 0.001 && keySettings.Show {
+		keyAlpha := keySettings.Opacity
+		keyScale := keySettings.Scale
+
+		keyLayout := newHudLayout(bmath.Origin.CentreRight, overlay.ScaledWidth-48*keyScale, overlay.ScaledHeight/2-64, 48*keyScale, 200*keyScale)
+		batch.SetCamera(overlay.layoutCamera(keyLayout, keySettings.Anchor, keySettings.XOffset, keySettings.YOffset, keySettings.Rotation))
 
 		batch.SetColor(1, 1, 1, alpha*keyAlpha)
 		batch.SetScale(keyScale, keyScale)
@@ -537,7 +657,90 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, colors []color2.Col
 	batch.SetCamera(prev)
 }
 
-func (overlay *ScoreOverlay) IsBroken(cursor *graphics.Cursor) bool {
+// drawText draws a single line of text that by default is placed on the left side of the screen at given height
+func (overlay *ScoreOverlay) drawText(batch *batch.QuadBatch, anchor string, xOffset, yOffset, rotation, y, size, alpha float64, text string) {
+	layout := newHudLayout(bmath.Origin.TopLeft, 0, y, overlay.font.GetWidthMonospaced(size, text), size*0.8)
+	batch.SetCamera(overlay.layoutCamera(layout, anchor, xOffset, yOffset, rotation))
+
+	batch.SetColor(1, 1, 1, alpha)
+	batch.SetScale(1, -1)
+	batch.SetSubScale(1, 1)
+
+	overlay.font.DrawMonospaced(batch, 0, y, size, text)
+
+	batch.ResetTransform()
+}
+
+func (overlay *ScoreOverlay) drawHitCounter(batch *batch.QuadBatch, alpha float64) {
+	hitSettings := settings.Gameplay.HitCounter
+
+	size := 20 * hitSettings.Scale
+	lineHeight := size * 1.2
+
+	results := []osu.HitResult{osu.Hit300, osu.Hit100, osu.Hit50, osu.Miss}
+	names := []string{"300", "100", "50", "Miss"}
+	hitColors := []color2.Color{{R: 0.2, G: 0.8, B: 1, A: 1}, {R: 0.44, G: 0.98, B: 0.18, A: 1}, {R: 0.85, G: 0.68, B: 0.27, A: 1}, {R: 1, G: 0.2, B: 0.2, A: 1}}
+
+	width := 0.0
+	texts := make([]string, len(results))
+
+	for i, result := range results {
+		texts[i] = fmt.Sprintf("%s: %d", names[i], overlay.ruleset.GetHitCount(overlay.cursor, result))
+		width = math.Max(width, overlay.font.GetWidthMonospaced(size, texts[i]))
+	}
+
+	height := lineHeight * float64(len(results))
+	top := overlay.ScaledHeight/2 - height/2
+
+	layout := newHudLayout(bmath.Origin.CentreLeft, 0, top, width, height)
+	batch.SetCamera(overlay.layoutCamera(layout, hitSettings.Anchor, hitSettings.XOffset, hitSettings.YOffset, hitSettings.Rotation))
+
+	batch.SetScale(1, -1)
+	batch.SetSubScale(1, 1)
+
+	for i, text := range texts {
+		batch.SetColor(float64(hitColors[i].R), float64(hitColors[i].G), float64(hitColors[i].B), alpha)
+		overlay.font.DrawMonospaced(batch, 0, top+float64(i)*lineHeight, size, text)
+	}
+
+	batch.ResetTransform()
+}
+
+// strainBarBounds returns top-left corner and size of StrainGraph progress bar on screen of given size
+func strainBarBounds(width, height float64) (vector.Vector2d, vector.Vector2d) {
+	size := vector.NewVec2d(width, height*50/768)
+
+	if settings.Gameplay.ProgressBarPosition == "Top" {
+		return vector.NewVec2d(0, 0), size
+	}
+
+	return vector.NewVec2d(0, height-size.Y), size
+}
+
+func formatAccuracy(acc float64) string {
+	if acc == 100 {
+		return fmt.Sprintf("%5.1f%%", acc)
+	}
+
+	return fmt.Sprintf("%5.2f%%", acc)
+}
+
+func (overlay *ScoreOverlay) getHealthMarker() *texture.TextureRegion {
+	if overlay.healthMarker != nil {
+		return overlay.healthMarker
+	}
+
+	switch {
+	case overlay.displayHp < 0.2 && overlay.healthDanger2 != nil:
+		return overlay.healthDanger2
+	case overlay.displayHp < 0.5 && overlay.healthDanger != nil:
+		return overlay.healthDanger
+	}
+
+	return overlay.healthKi
+}
+
+func (overlay *ScoreOverlay) IsBroken(cursor *graphics.CursorState) bool {
 	return false
 }
 
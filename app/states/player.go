@@ -3,6 +3,7 @@ package states
 import (
 	"fmt"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/wieku/danser-go/app/audio"
 	"github.com/wieku/danser-go/app/beatmap"
 	"github.com/wieku/danser-go/app/beatmap/difficulty"
 	"github.com/wieku/danser-go/app/bmath"
@@ -12,7 +13,9 @@ import (
 	"github.com/wieku/danser-go/app/graphics"
 	"github.com/wieku/danser-go/app/graphics/font"
 	"github.com/wieku/danser-go/app/graphics/gui/drawables"
+	"github.com/wieku/danser-go/app/recording"
 	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/app/skin"
 	"github.com/wieku/danser-go/app/states/components/common"
 	"github.com/wieku/danser-go/app/states/components/containers"
 	"github.com/wieku/danser-go/app/states/components/overlays"
@@ -35,6 +38,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -42,11 +47,13 @@ type Player struct {
 	font        *font.Font
 	bMap        *beatmap.BeatMap
 	bloomEffect *effects.BloomEffect
+	postProcess *effects.PostProcessEffect
 	lastTime    int64
 	progressMsF float64
 	progressMs  int64
 	batch       *batch2.QuadBatch
 	controller  dance.Controller
+	cursors     []*graphics.CursorRenderer
 	background  *common.Background
 	BgScl       vector.Vector2d
 	Scl         float64
@@ -99,9 +106,23 @@ type Player struct {
 	updateLimiter *frame.Limiter
 
 	objectContainer *containers.HitObjectContainer
+
+	stopped    int32 // accessed atomically, non-zero when update threads should exit
+	threads    sync.WaitGroup
+	switchSkin bool
+
+	// guards update against skin reloads done on the main thread
+	updateMutex sync.Mutex
+
+	mapEnd float64
+
+	recordTime      float64
+	recordFrameTime float64
+	musicCounter    float64
+	recordMusic     recording.Music
 }
 
-func NewPlayer(beatMap *beatmap.BeatMap) *Player {
+func NewPlayer(beatMap *beatmap.BeatMap) (*Player, error) {
 	player := new(Player)
 
 	graphics.LoadTextures()
@@ -167,13 +188,20 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 		player.controller = dance.NewPlayerController()
 
 		player.controller.SetBeatMap(player.bMap)
-		player.controller.InitCursors()
+
+		if err := player.controller.InitCursors(); err != nil {
+			return nil, err
+		}
+
 		player.overlay = overlays.NewScoreOverlay(player.controller.(*dance.PlayerController).GetRuleset(), player.controller.GetCursors()[0])
 	} else if settings.KNOCKOUT {
 		controller := dance.NewReplayController()
 		player.controller = controller
 		player.controller.SetBeatMap(player.bMap)
-		player.controller.InitCursors()
+
+		if err := player.controller.InitCursors(); err != nil {
+			return nil, err
+		}
 
 		if settings.PLAYERS == 1 {
 			player.overlay = overlays.NewScoreOverlay(player.controller.(*dance.ReplayController).GetRuleset(), player.controller.GetCursors()[0])
@@ -183,7 +211,20 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 	} else {
 		player.controller = dance.NewGenericController()
 		player.controller.SetBeatMap(player.bMap)
-		player.controller.InitCursors()
+
+		if err := player.controller.InitCursors(); err != nil {
+			return nil, err
+		}
+
+		if settings.Dance.Humanizer.Enabled {
+			controller := player.controller.(*dance.GenericController)
+			controller.InitScoring()
+			player.overlay = overlays.NewScoreOverlay(controller.GetRuleset(), controller.GetCursors()[0])
+		}
+	}
+
+	for _, cursor := range player.controller.GetCursors() {
+		player.cursors = append(player.cursors, graphics.NewCursorRenderer(cursor))
 	}
 
 	player.lastTime = -1
@@ -252,6 +293,8 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 
 	player.volumeGlider.AddEvent(tmE, tmE+settings.Playfield.FadeOutTime*1000, 0.0)
 
+	player.mapEnd = tmE + fadeOut
+
 	player.epiGlider = animation.NewGlider(0)
 
 	if settings.Playfield.SeizureWarning.Enabled {
@@ -290,7 +333,12 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 		player.cursorGlider.AddEvent(float64(bd.EndTime)-100, float64(bd.EndTime), 1.0)
 	}
 
-	musicPlayer := bass.NewTrack(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.Audio))
+	var musicPlayer *bass.Track
+	if settings.RECORD {
+		musicPlayer = bass.NewTrackDecode(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.Audio))
+	} else {
+		musicPlayer = bass.NewTrack(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.Audio))
+	}
 	player.background.SetTrack(musicPlayer)
 	player.visualiser = drawables.NewVisualiser(player.cookieSize*0.66, player.cookieSize*2, vector.NewVec2d(0, 0))
 	player.visualiser.SetTrack(musicPlayer)
@@ -301,6 +349,10 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 	player.bloomEffect = effects.NewBloomEffect(int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight()))
 	player.blur = effects.NewBlurEffect(int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight()))
 
+	if settings.Playfield.PostProcess.Enabled {
+		player.postProcess = newPostProcess(int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight()))
+	}
+
 	player.background.Update(0, settings.Graphics.GetWidthF()/2, settings.Graphics.GetHeightF()/2)
 
 	player.profilerU = frame.NewCounter()
@@ -309,7 +361,16 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 
 	player.updateLimiter = frame.NewLimiter(player.baseLimit)
 
+	// when recording, UpdateRecording drives the player instead
+	if settings.RECORD {
+		return player, nil
+	}
+
+	player.threads.Add(2)
+
 	go func() {
+		defer player.threads.Done()
+
 		defer func() {
 			if err := recover(); err != nil {
 				log.Println("panic:", err)
@@ -326,7 +387,7 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 
 		var lastT = qpc.GetNanoTime()
 
-		for {
+		for !player.isStopped() {
 			currtime := qpc.GetNanoTime()
 
 			player.profilerU.PutSample(float64(currtime-lastT) / 1000000.0)
@@ -337,119 +398,272 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 				player.progressMsF = musicPlayer.GetPosition()*1000 + float64(settings.Audio.Offset)
 			}
 
-			if player.progressMsF >= player.startPoint && !player.start {
-				musicPlayer.Play()
-				musicPlayer.SetTempo(settings.SPEED)
-				musicPlayer.SetPitch(settings.PITCH)
+			player.update(float64(currtime-lastT) / 1000000)
 
-				if ov, ok := player.overlay.(*overlays.ScoreOverlay); ok {
-					ov.SetMusic(musicPlayer)
-				}
+			lastT = currtime
 
-				musicPlayer.SetPosition(player.startPoint / 1000)
+			player.updateLimiter.Sync()
+		}
+	}()
 
-				discord.SetDuration(int64((musicPlayer.GetLength() - musicPlayer.GetPosition()) * 1000 / settings.SPEED))
+	go func() {
+		defer player.threads.Done()
 
-				if player.overlay == nil {
-					discord.UpdateDance(settings.TAG, settings.DIVIDES)
-				}
+		for !player.isStopped() {
+			musicPlayer.Update()
 
-				player.start = true
-			}
+			player.updateMusic()
 
-			if player.progressMsF >= player.startPoint-player.bMap.Diff.Preempt {
-				if _, ok := player.controller.(*dance.GenericController); ok {
-					player.bMap.Update(int64(player.progressMsF))
-				}
+			time.Sleep(15 * time.Millisecond)
+		}
+	}()
 
-				player.objectContainer.Update(player.progressMsF)
-			}
+	return player, nil
+}
 
-			if player.progressMsF >= player.startPoint-player.bMap.Diff.Preempt || settings.PLAY {
-				player.controller.Update(int64(player.progressMsF), float64(currtime-lastT)/1000000)
-			}
+func (player *Player) update(delta float64) {
+	player.updateMutex.Lock()
+	defer player.updateMutex.Unlock()
 
-			if player.overlay != nil {
-				player.overlay.Update(int64(player.progressMsF))
-			}
+	if player.progressMsF >= player.startPoint && !player.start {
+		player.startMusic()
+	}
 
-			bTime := player.bMap.Timings.Current.BaseBpm
+	if player.progressMsF >= player.startPoint-player.bMap.Diff.Preempt {
+		if _, ok := player.controller.(*dance.GenericController); ok {
+			player.bMap.Update(int64(player.progressMsF))
+		}
 
-			if bTime != player.lastBeatLength {
-				player.lastBeatLength = bTime
-				player.lastBeatStart = float64(player.bMap.Timings.Current.Time)
-				player.lastBeatProg = int64((player.progressMsF-player.lastBeatStart)/player.lastBeatLength) - 1
-			}
+		player.objectContainer.Update(player.progressMsF)
+	}
 
-			if int64(float64(player.progressMsF-player.lastBeatStart)/player.lastBeatLength) > player.lastBeatProg {
-				player.lastBeatProg++
-			}
+	if player.progressMsF >= player.startPoint-player.bMap.Diff.Preempt || settings.PLAY {
+		player.controller.Update(int64(player.progressMsF), delta)
 
-			player.beatProgress = float64(player.progressMsF-player.lastBeatStart)/player.lastBeatLength - float64(player.lastBeatProg)
-			player.visualiser.Update(player.progressMsF)
+		for _, cursor := range player.cursors {
+			cursor.Update(delta)
+		}
+	}
 
-			var offset vector.Vector2d
+	if player.overlay != nil {
+		player.overlay.Update(int64(player.progressMsF))
+	}
 
-			for _, c := range player.controller.GetCursors() {
-				offset = offset.Add(player.camera.Project(c.Position.Copy64()).Mult(vector.NewVec2d(2/settings.Graphics.GetWidthF(), -2/settings.Graphics.GetHeightF())))
-			}
+	bTime := player.bMap.Timings.Current.BaseBpm
 
-			offset = offset.Scl(1 / float64(len(player.controller.GetCursors())))
+	if bTime != player.lastBeatLength {
+		player.lastBeatLength = bTime
+		player.lastBeatStart = float64(player.bMap.Timings.Current.Time)
+		player.lastBeatProg = int64((player.progressMsF-player.lastBeatStart)/player.lastBeatLength) - 1
+	}
 
-			player.background.Update(player.progressMsF, offset.X*player.cursorGlider.GetValue(), offset.Y*player.cursorGlider.GetValue())
+	if int64(float64(player.progressMsF-player.lastBeatStart)/player.lastBeatLength) > player.lastBeatProg {
+		player.lastBeatProg++
+	}
 
-			player.epiGlider.Update(player.progressMsF)
-			player.dimGlider.Update(player.progressMsF)
-			player.blurGlider.Update(player.progressMsF)
-			player.fxGlider.Update(player.progressMsF)
-			player.cursorGlider.Update(player.progressMsF)
-			player.playersGlider.Update(player.progressMsF)
-			player.hudGlider.Update(player.progressMsF)
-			player.unfold.Update(player.progressMsF)
+	player.beatProgress = float64(player.progressMsF-player.lastBeatStart)/player.lastBeatLength - float64(player.lastBeatProg)
+	player.visualiser.Update(player.progressMsF)
 
-			player.volumeGlider.Update(player.progressMsF)
-			player.musicPlayer.SetVolumeRelative(player.volumeGlider.GetValue())
+	var offset vector.Vector2d
 
-			lastT = currtime
+	for _, c := range player.controller.GetCursors() {
+		offset = offset.Add(player.camera.Project(c.Position.Copy64()).Mult(vector.NewVec2d(2/settings.Graphics.GetWidthF(), -2/settings.Graphics.GetHeightF())))
+	}
 
-			player.updateLimiter.Sync()
+	offset = offset.Scl(1 / float64(len(player.controller.GetCursors())))
+
+	player.background.Update(player.progressMsF, offset.X*player.cursorGlider.GetValue(), offset.Y*player.cursorGlider.GetValue())
+
+	player.epiGlider.Update(player.progressMsF)
+	player.dimGlider.Update(player.progressMsF)
+	player.blurGlider.Update(player.progressMsF)
+	player.fxGlider.Update(player.progressMsF)
+	player.cursorGlider.Update(player.progressMsF)
+	player.playersGlider.Update(player.progressMsF)
+	player.hudGlider.Update(player.progressMsF)
+	player.unfold.Update(player.progressMsF)
+
+	player.volumeGlider.Update(player.progressMsF)
+	player.musicPlayer.SetVolumeRelative(player.volumeGlider.GetValue())
+
+	if settings.RECORD && player.start {
+		volume := settings.Audio.GeneralVolume * settings.Audio.MusicVolume * player.volumeGlider.GetValue()
+
+		if points := player.recordMusic.Volume; len(points) == 0 || points[len(points)-1].Volume != volume {
+			player.recordMusic.Volume = append(points, recording.VolumePoint{Time: player.recordTime, Volume: volume})
 		}
-	}()
+	}
+}
 
-	go func() {
-		for {
-			musicPlayer.Update()
+// CycleSkin requests a switch to the next skin from OsuSkinsDir, it's applied by the next ApplySkinSwitch call.
+func (player *Player) CycleSkin() {
+	player.switchSkin = true
+}
 
-			target := bmath.ClampF64(musicPlayer.GetBoost()*(settings.Audio.BeatScale-1.0)+1.0, 1.0, settings.Audio.BeatScale) //math.Min(1.4*settings.Audio.BeatScale, math.Max(math.Sin(musicPlayer.GetBeat()*math.Pi/2)*0.4*settings.Audio.BeatScale+1.0, 1.0))
+// ApplySkinSwitch loads the skin requested by CycleSkin. It has to be called on the main thread before Draw.
+func (player *Player) ApplySkinSwitch() {
+	if !player.switchSkin {
+		return
+	}
 
-			ratio1 := 15 / 16.6666666666667
+	player.switchSkin = false
 
-			player.vol = player.musicPlayer.GetLevelCombined()
-			player.volAverage = player.volAverage*0.9 + player.vol*0.1
+	player.updateMutex.Lock()
+	defer player.updateMutex.Unlock()
 
-			vprog := 1 - ((player.vol - player.volAverage) / 0.5)
-			pV := math.Min(1.0, math.Max(0.0, 1.0-(vprog*0.5+player.beatProgress*0.5)))
+	current := skin.CurrentSkin
+	skins := skin.ListSkins()
 
-			ratio := math.Pow(0.5, ratio1)
+	index := -1
+	for i, name := range skins {
+		if name == current {
+			index = i
+		}
+	}
 
-			player.progress = player.lastProgress*ratio + (pV)*(1-ratio)
-			player.lastProgress = player.progress
+	// skins that fail to load are skipped, the current one is kept if none of them works
+	switched := false
 
-			if settings.Audio.BeatUseTimingPoints {
-				player.Scl = 1 + player.progress*(settings.Audio.BeatScale-1.0)
-			} else {
-				if player.Scl < target {
-					player.Scl += (target - player.Scl) * 30 / 100
-				} else if player.Scl > target {
-					player.Scl -= (player.Scl - target) * 15 / 100
-				}
+	for i := 1; i <= len(skins) && !switched; i++ {
+		name := skins[(index+i)%len(skins)]
+		if name == current {
+			continue
+		}
+
+		if err := skin.SwitchSkin(name); err != nil {
+			log.Println(err)
+			continue
+		}
+
+		switched = true
+	}
+
+	if !switched {
+		log.Println("No other skin could be loaded, keeping", current)
+		return
+	}
+
+	audio.LoadSamples()
+	graphics.ReloadCursorTextures()
+
+	player.objectContainer.ReloadTextures()
+
+	if player.overlay != nil {
+		player.overlay.ReloadTextures()
+	}
+}
+
+func (player *Player) startMusic() {
+	if settings.RECORD {
+		// music isn't played when recording, it's mixed offline from this point
+		player.progressMsF = player.startPoint
+
+		player.recordMusic = recording.Music{
+			Path:     filepath.Join(settings.General.OsuSongsDir, player.bMap.Dir, player.bMap.Audio),
+			Start:    player.recordTime,
+			Position: player.startPoint / 1000,
+			Tempo:    settings.SPEED,
+			Pitch:    settings.PITCH,
+		}
+	} else {
+		player.musicPlayer.Play()
+	}
+
+	player.musicPlayer.SetTempo(settings.SPEED)
+	player.musicPlayer.SetPitch(settings.PITCH)
+
+	if ov, ok := player.overlay.(*overlays.ScoreOverlay); ok {
+		ov.SetMusic(player.musicPlayer)
+	}
+
+	player.musicPlayer.SetPosition(player.startPoint / 1000)
+
+	discord.SetDuration(int64((player.musicPlayer.GetLength() - player.musicPlayer.GetPosition()) * 1000 / settings.SPEED))
+
+	if player.overlay == nil {
+		discord.UpdateDance(settings.TAG, settings.DIVIDES)
+	}
+
+	player.start = true
+}
+
+func (player *Player) updateMusic() {
+	target := bmath.ClampF64(player.musicPlayer.GetBoost()*(settings.Audio.BeatScale-1.0)+1.0, 1.0, settings.Audio.BeatScale) //math.Min(1.4*settings.Audio.BeatScale, math.Max(math.Sin(musicPlayer.GetBeat()*math.Pi/2)*0.4*settings.Audio.BeatScale+1.0, 1.0))
+
+	ratio1 := 15 / 16.6666666666667
+
+	player.vol = player.musicPlayer.GetLevelCombined()
+	player.volAverage = player.volAverage*0.9 + player.vol*0.1
+
+	vprog := 1 - ((player.vol - player.volAverage) / 0.5)
+	pV := math.Min(1.0, math.Max(0.0, 1.0-(vprog*0.5+player.beatProgress*0.5)))
+
+	ratio := math.Pow(0.5, ratio1)
+
+	player.progress = player.lastProgress*ratio + (pV)*(1-ratio)
+	player.lastProgress = player.progress
+
+	if settings.Audio.BeatUseTimingPoints {
+		player.Scl = 1 + player.progress*(settings.Audio.BeatScale-1.0)
+	} else {
+		if player.Scl < target {
+			player.Scl += (target - player.Scl) * 30 / 100
+		} else if player.Scl > target {
+			player.Scl -= (player.Scl - target) * 15 / 100
+		}
+	}
+}
+
+// UpdateRecording advances the player by frameTime ms of recording time in 1ms steps. It replaces update threads when recording.
+func (player *Player) UpdateRecording(frameTime float64) {
+	player.recordFrameTime = frameTime
+
+	for elapsed := 0.0; elapsed < frameTime; elapsed++ {
+		delta := math.Min(1, frameTime-elapsed)
+
+		player.recordTime += delta
+		bass.SetCaptureTime(player.recordTime)
+
+		if player.start {
+			player.progressMsF += delta * settings.SPEED
+		} else {
+			player.progressMsF += delta
+		}
+
+		player.update(delta)
+
+		// music analysis runs at the same rate as its thread in normal mode
+		player.musicCounter += delta
+		if player.musicCounter >= 15 {
+			player.musicCounter -= 15
+
+			if player.start {
+				player.musicPlayer.SetPosition(player.progressMsF / 1000)
+				player.musicPlayer.Update()
 			}
 
-			time.Sleep(15 * time.Millisecond)
+			player.updateMusic()
 		}
-	}()
+	}
+
+	if storyboard := player.background.GetStoryboard(); storyboard != nil {
+		storyboard.Update(int64(player.progressMsF))
+	}
+}
+
+// IsFinished returns true when the map has ended and faded out.
+func (player *Player) IsFinished() bool {
+	return player.progressMsF >= player.mapEnd
+}
+
+// GetRecordingTime returns how many ms were recorded by UpdateRecording.
+func (player *Player) GetRecordingTime() float64 {
+	return player.recordTime
+}
 
-	return player
+// GetRecordedMusic describes how the music should be mixed into the recording, Path is empty if it didn't start yet.
+func (player *Player) GetRecordedMusic() recording.Music {
+	return player.recordMusic
 }
 
 func (player *Player) Show() {
@@ -464,6 +678,10 @@ func (player *Player) Draw(float64) {
 	tim := qpc.GetNanoTime()
 	timMs := float64(tim-player.lastTime) / 1000000.0
 
+	if settings.RECORD {
+		timMs = player.recordFrameTime
+	}
+
 	fps := player.profiler.GetFPS()
 
 	player.updateLimiter.FPS = bmath.ClampI(int(fps*1.2), player.baseLimit, 10000)
@@ -483,13 +701,25 @@ func (player *Player) Draw(float64) {
 
 	bgAlpha := player.dimGlider.GetValue()
 
-	cameras := player.camera.GenRotated(settings.DIVIDES, -2*math.Pi/float64(settings.DIVIDES) /**player.unfold.GetValue()*/)
+	cameras := player.camera.GenSymmetric(settings.DIVIDES)
 	cameras1 := player.camera1.GenRotated(settings.DIVIDES, -2*math.Pi/float64(settings.DIVIDES) /**player.unfold.GetValue()*/)
 
 	if settings.Playfield.Background.FlashToTheBeat {
 		bgAlpha *= player.Scl
 	}
 
+	if player.postProcess != nil {
+		kiai := 0.0
+		if player.bMap.Timings.Current.Kiai {
+			kiai = 1
+		}
+
+		player.postProcess.SetUniform("time", player.progressMsF/1000)
+		player.postProcess.SetUniform("beatScale", player.Scl)
+		player.postProcess.SetUniform("kiai", kiai)
+		player.postProcess.Begin()
+	}
+
 	player.background.Draw(player.progressMs, player.batch, player.blurGlider.GetValue(), bgAlpha, cameras1[0])
 
 	if player.start {
@@ -606,7 +836,7 @@ func (player *Player) Draw(float64) {
 	player.background.DrawOverlay(player.progressMs, player.batch, bgAlpha, cameras1[0])
 
 	if settings.Playfield.DrawCursors {
-		for _, g := range player.controller.GetCursors() {
+		for _, g := range player.cursors {
 			g.UpdateRenderer()
 		}
 
@@ -617,8 +847,8 @@ func (player *Player) Draw(float64) {
 		for j := 0; j < settings.DIVIDES; j++ {
 			player.batch.SetCamera(cameras[j])
 
-			for i, g := range player.controller.GetCursors() {
-				if player.overlay != nil && player.overlay.IsBroken(g) {
+			for i, g := range player.cursors {
+				if player.overlay != nil && player.overlay.IsBroken(g.GetState()) {
 					continue
 				}
 
@@ -660,6 +890,10 @@ func (player *Player) Draw(float64) {
 		player.bloomEffect.EndAndRender()
 	}
 
+	if player.postProcess != nil {
+		player.postProcess.EndAndRender()
+	}
+
 	if settings.DEBUG || settings.Graphics.ShowFPS {
 		player.batch.Begin()
 		player.batch.SetColor(1, 1, 1, 1)
@@ -784,6 +1018,23 @@ func (player *Player) Hide() {
 
 }
 
+// GetTime returns the current playback position in milliseconds.
+func (player *Player) GetTime() float64 {
+	return player.progressMsF
+}
+
+func (player *Player) isStopped() bool {
+	return atomic.LoadInt32(&player.stopped) != 0
+}
+
+// Dispose stops update threads and music so another Player can take over. It waits until update threads exit.
 func (player *Player) Dispose() {
+	atomic.StoreInt32(&player.stopped, 1)
+	player.threads.Wait()
 
+	player.musicPlayer.Stop()
+
+	if storyboard := player.background.GetStoryboard(); storyboard != nil {
+		storyboard.StopThread()
+	}
 }
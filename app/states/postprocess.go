@@ -0,0 +1,74 @@
+package states
+
+import (
+	"fmt"
+	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/framework/graphics/effects"
+	"log"
+)
+
+// newPostProcess compiles passes listed in settings, ones that fail are logged and skipped.
+// Returns nil if no pass could be used.
+func newPostProcess(width, height int) *effects.PostProcessEffect {
+	effect := effects.NewPostProcessEffect(width, height)
+
+	for _, pass := range settings.Playfield.PostProcess.Passes {
+		if pass == nil {
+			continue
+		}
+
+		uniforms, err := convertUniforms(pass.Uniforms)
+		if err != nil {
+			log.Println(fmt.Sprintf("Post-processing: %s: %s", pass.Shader, err))
+			continue
+		}
+
+		if err = effect.AddPass(pass.Shader, uniforms); err != nil {
+			log.Println("Post-processing: skipping pass,", err)
+			continue
+		}
+
+		log.Println("Post-processing: loaded", pass.Shader)
+	}
+
+	if effect.GetPasses() == 0 {
+		log.Println("Post-processing: no shaders loaded, drawing without post-processing")
+		return nil
+	}
+
+	return effect
+}
+
+func convertUniforms(values map[string]interface{}) (map[string][]float64, error) {
+	uniforms := make(map[string][]float64)
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case float64:
+			uniforms[name] = []float64{v}
+		case bool:
+			if v {
+				uniforms[name] = []float64{1}
+			} else {
+				uniforms[name] = []float64{0}
+			}
+		case []interface{}:
+			if len(v) < 1 || len(v) > 4 {
+				return nil, fmt.Errorf("uniform \"%s\" has %d values, expected 1-4", name, len(v))
+			}
+
+			for _, component := range v {
+				f, ok := component.(float64)
+				if !ok {
+					return nil, fmt.Errorf("uniform \"%s\" has a non-number value: %v", name, component)
+				}
+
+				uniforms[name] = append(uniforms[name], f)
+			}
+		default:
+			return nil, fmt.Errorf("uniform \"%s\" has an invalid value: %v", name, value)
+		}
+	}
+
+	return uniforms, nil
+}
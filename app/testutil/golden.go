@@ -0,0 +1,110 @@
+// Package testutil contains helpers for golden file tests.
+//
+// Golden files are kept in testdata/golden of the tested package and are compared on every run.
+// When a change of results is intended, regenerate them with:
+//
+//	go test ./app/beatmap ./app/dance ./app/rulesets/osu -update
+//
+// and review the diff before committing.
+package testutil
+
+import (
+	"flag"
+	"fmt"
+	"github.com/wieku/danser-go/app/beatmap"
+	"github.com/wieku/danser-go/app/settings"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate golden files instead of comparing against them")
+
+// FixturePath returns the path of a file in the shared testdata directory at the root of the repository.
+func FixturePath(elem ...string) string {
+	_, file, _, _ := runtime.Caller(0)
+
+	return filepath.Join(append([]string{filepath.Dir(file), "..", "..", "testdata"}, elem...)...)
+}
+
+// LoadBeatMap parses a fixture map from testdata/maps/<dir>/<file> together with its objects and timing points.
+// Objects don't get their sprites, so only the gameplay side of them can be used.
+func LoadBeatMap(t *testing.T, dir, file string) *beatmap.BeatMap {
+	t.Helper()
+
+	settings.General.OsuSongsDir = FixturePath("maps")
+
+	bMap := beatmap.NewBeatMap()
+	bMap.Dir = dir
+	bMap.File = file
+
+	if err := beatmap.ParseBeatMap(bMap); err != nil {
+		t.Fatalf("failed to parse %s: %s", file, err)
+	}
+
+	beatmap.ParseTimingPointsAndPauses(bMap)
+	beatmap.ParseObjects(bMap)
+
+	return bMap
+}
+
+// Golden collects lines of test output that are compared with a golden file.
+type Golden struct {
+	builder strings.Builder
+}
+
+func (golden *Golden) Printf(format string, args ...interface{}) {
+	golden.builder.WriteString(fmt.Sprintf(format, args...))
+	golden.builder.WriteString("\n")
+}
+
+// Check compares the collected output with testdata/golden/<name>.golden, or rewrites that file if -update is set.
+func (golden *Golden) Check(t *testing.T, name string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	actual := golden.builder.String()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run with -update to create it: %s", err)
+	}
+
+	if actual == string(expected) {
+		return
+	}
+
+	actualLines := strings.Split(actual, "\n")
+	expectedLines := strings.Split(string(expected), "\n")
+
+	for i := 0; i < len(actualLines) || i < len(expectedLines); i++ {
+		var a, e string
+
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+
+		if a != e {
+			t.Fatalf("%s differs at line %d:\n\texpected: %s\n\tactual:   %s\nrun with -update if the change is intended", path, i+1, e, a)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package utils
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archives that couldn't be imported are moved here (relative to danser's directory) together with an error report.
+const quarantineDir = "failed-imports"
+
+var ErrIllegalPath = errors.New("illegal file path")
+var ErrSymlink = errors.New("symbolic links are not allowed")
+var ErrDuplicate = errors.New("duplicate entry")
+var ErrSizeMismatch = errors.New("entry is larger than declared")
+
+// ArchiveError describes a problem with a single archive entry.
+type ArchiveError struct {
+	File string
+	Err  error
+}
+
+func (e *ArchiveError) Error() string {
+	return fmt.Sprintf("%s: %s", e.File, e.Err)
+}
+
+func (e *ArchiveError) Unwrap() error {
+	return e.Err
+}
+
+// EntryPath returns the path an archive entry would be extracted to, or ErrIllegalPath if it would end up outside dest.
+func EntryPath(dest string, name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+
+	if name == "" || strings.HasPrefix(name, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" || strings.ContainsRune(name, 0) {
+		return "", ErrIllegalPath
+	}
+
+	dest = filepath.Clean(dest)
+	path := filepath.Join(dest, filepath.FromSlash(name))
+
+	if rel, err := filepath.Rel(dest, path); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", ErrIllegalPath
+	}
+
+	return path, nil
+}
+
+// ValidateArchive checks every entry of an archive before anything is extracted.
+// Entries escaping dest (zip-slip), symbolic links and entries colliding on case-insensitive file systems are rejected.
+func ValidateArchive(r *zip.Reader, dest string) (errs []error) {
+	seen := make(map[string]bool)
+
+	for _, f := range r.File {
+		path, err := EntryPath(dest, f.Name)
+		if err != nil {
+			errs = append(errs, &ArchiveError{f.Name, err})
+			continue
+		}
+
+		if f.Mode()&os.ModeSymlink != 0 {
+			errs = append(errs, &ArchiveError{f.Name, ErrSymlink})
+			continue
+		}
+
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		key := strings.ToLower(path)
+		if seen[key] {
+			errs = append(errs, &ArchiveError{f.Name, ErrDuplicate})
+			continue
+		}
+
+		seen[key] = true
+	}
+
+	return
+}
+
+// ExtractArchive extracts a validated archive into dest. Entries are extracted into a temporary directory next to dest first,
+// so a failed extraction leaves nothing behind. Extraction continues after a failed entry, all failures are returned in errs.
+// If dest already exists, both are merged: files with identical content are left untouched and differing ones are saved
+// under a free name instead of being overwritten.
+func ExtractArchive(r *zip.Reader, dest string) (extracted []string, errs []error) {
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return nil, []error{err}
+	}
+
+	tmp, err := ioutil.TempDir(filepath.Dir(dest), ".extract-")
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	defer os.RemoveAll(tmp)
+
+	// TempDir creates the directory accessible only by the owner, it becomes dest when nothing is there yet
+	if err = os.Chmod(tmp, 0755); err != nil {
+		return nil, []error{err}
+	}
+
+	var files []string
+
+	for _, f := range r.File {
+		path, err := EntryPath(tmp, f.Name)
+		if err != nil {
+			errs = append(errs, &ArchiveError{f.Name, err})
+			continue
+		}
+
+		if f.FileInfo().IsDir() {
+			if err = os.MkdirAll(path, os.ModePerm); err != nil {
+				errs = append(errs, &ArchiveError{f.Name, err})
+			}
+
+			continue
+		}
+
+		rel, _ := filepath.Rel(tmp, path)
+
+		if sameContent(filepath.Join(dest, rel), f) {
+			continue
+		}
+
+		if err = extractFile(f, path); err != nil {
+			errs = append(errs, &ArchiveError{f.Name, err})
+			continue
+		}
+
+		files = append(files, rel)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if _, err = os.Lstat(dest); os.IsNotExist(err) {
+		if err = os.Rename(tmp, dest); err != nil {
+			return nil, []error{err}
+		}
+
+		for _, rel := range files {
+			extracted = append(extracted, filepath.Join(dest, rel))
+		}
+
+		return
+	}
+
+	for _, rel := range files {
+		path := filepath.Join(dest, rel)
+
+		target := freeFilePath(path)
+		if target != path {
+			log.Println("File", path, "already exists with different content, saving as", filepath.Base(target))
+		}
+
+		if err = MoveFile(filepath.Join(tmp, rel), target); err != nil {
+			errs = append(errs, &ArchiveError{filepath.ToSlash(rel), err})
+			break
+		}
+
+		extracted = append(extracted, target)
+	}
+
+	// roll back, so a failed merge doesn't leave the existing directory half-updated
+	if len(errs) > 0 {
+		for _, path := range extracted {
+			os.Remove(path)
+		}
+
+		return nil, errs
+	}
+
+	return
+}
+
+// freeFilePath returns path if nothing exists there yet, otherwise a numbered variant of it, like "name (2).ext".
+func freeFilePath(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			return path
+		}
+
+		path = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+}
+
+func extractFile(f *zip.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	defer rc.Close()
+
+	outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.CopyN(outFile, rc, int64(f.UncompressedSize64)+1)
+	if err == io.EOF {
+		err = nil
+	}
+
+	if err == nil && n > int64(f.UncompressedSize64) {
+		err = ErrSizeMismatch
+	}
+
+	if err1 := outFile.Close(); err == nil {
+		err = err1
+	}
+
+	if err != nil {
+		os.Remove(path)
+	}
+
+	return err
+}
+
+func sameContent(path string, f *zip.File) bool {
+	stat, err := os.Stat(path)
+	if err != nil || stat.IsDir() || uint64(stat.Size()) != f.UncompressedSize64 {
+		return false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+
+	defer file.Close()
+
+	hash := crc32.NewIEEE()
+	if _, err = io.Copy(hash, file); err != nil {
+		return false
+	}
+
+	return hash.Sum32() == f.CRC32
+}
+
+// MoveFile renames a file, falling back to copy and delete when source and target are on different devices.
+func MoveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		in.Close()
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+
+	in.Close()
+
+	if err1 := out.Close(); err == nil {
+		err = err1
+	}
+
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Remove(src)
+}
+
+// QuarantineArchive moves an archive that failed to import to quarantineDir and writes errs next to it.
+func QuarantineArchive(archive string, errs []error) {
+	if _, err := os.Stat(archive); err != nil {
+		return
+	}
+
+	target := filepath.Join(quarantineDir, time.Now().Format("20060102-150405")+"-"+filepath.Base(archive))
+
+	if err := MoveFile(archive, target); err != nil {
+		log.Println("Failed to quarantine", archive+":", err)
+		return
+	}
+
+	report := "Failed to import " + archive + ":\n"
+	for _, err := range errs {
+		report += err.Error() + "\n"
+	}
+
+	if err := ioutil.WriteFile(target+".txt", []byte(report), 0644); err != nil {
+		log.Println(err)
+	}
+
+	log.Println("Archive moved to", target)
+}
@@ -0,0 +1,21 @@
+// Like smoothstep, but linear
+float linearstep(float edge0, float edge1, float x) {
+    return clamp((x - edge0) / (edge1 - edge0), 0.0, 1.0);
+}
+
+float inQuad(float t) {
+    return t * t;
+}
+
+float outQuad(float t) {
+    return t * (2.0 - t);
+}
+
+float inOutQuad(float t) {
+    return t < 0.5 ? 2.0 * t * t : -1.0 + (4.0 - 2.0 * t) * t;
+}
+
+float outCubic(float t) {
+    t -= 1.0;
+    return t * t * t + 1.0;
+}
@@ -0,0 +1,219 @@
+package bass
+
+/*
+#include "bass.h"
+*/
+import "C"
+
+import (
+	"math"
+	"sync"
+)
+
+// SampleEvent is a sample playback captured instead of being played.
+type SampleEvent struct {
+	// Time range in ms given by SetCaptureTime. One-shot samples end when their data ends, End is set only for loops
+	Time, End float64
+
+	Sample  *Sample
+	Volume  float64
+	Balance float64
+
+	Loop bool
+
+	// Playback speed relative to sample's own frequency
+	Speed float64
+
+	// Position in the sample in seconds where playback starts
+	Offset float64
+}
+
+// loopState tracks a captured looped channel which can be paused, resumed and have its rate changed.
+type loopState struct {
+	sample          *Sample
+	volume, balance float64
+
+	// index of the playing event, -1 if paused
+	event int
+
+	speed  float64
+	offset float64
+}
+
+var capturing bool
+var captureTime float64
+var captured []SampleEvent
+var captureLoops map[SubSample]*loopState
+var captureHandle SubSample
+var captureMutex sync.Mutex
+
+// StartCapture makes Sample play functions record SampleEvents instead of playing them, so they can be mixed offline.
+func StartCapture() {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+
+	capturing = true
+	captureTime = 0
+	captured = nil
+	captureLoops = make(map[SubSample]*loopState)
+	captureHandle = 0
+}
+
+// SetCaptureTime sets the time assigned to the next captured events.
+func SetCaptureTime(time float64) {
+	captureMutex.Lock()
+	captureTime = time
+	captureMutex.Unlock()
+}
+
+// StopCapture returns to normal playback and returns all captured events, loops still playing end at the last capture time.
+func StopCapture() []SampleEvent {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+
+	for _, loop := range captureLoops {
+		loop.pause()
+	}
+
+	capturing = false
+
+	events := captured
+	captured = nil
+	captureLoops = nil
+
+	return events
+}
+
+// capture returns true if the sample was captured and shouldn't be played. Looped samples get a handle
+// that can be used with SetRate, StopSample, PauseSample and PlaySample.
+func capture(sample *Sample, volume, balance float64, loop bool) (SubSample, bool) {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+
+	if !capturing {
+		return 0, false
+	}
+
+	if !loop {
+		captured = append(captured, SampleEvent{
+			Time:    captureTime,
+			Sample:  sample,
+			Volume:  volume,
+			Balance: balance,
+			Speed:   1,
+		})
+
+		return 0, true
+	}
+
+	captureHandle++
+
+	state := &loopState{
+		sample:  sample,
+		volume:  volume,
+		balance: balance,
+		event:   -1,
+		speed:   1,
+	}
+
+	state.resume()
+
+	captureLoops[captureHandle] = state
+
+	return captureHandle, true
+}
+
+// captureLoop runs action on a captured loop, returns false if the channel is a real one.
+func captureLoop(channel SubSample, action func(state *loopState)) bool {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+
+	if !capturing {
+		return false
+	}
+
+	if state, ok := captureLoops[channel]; ok {
+		action(state)
+	}
+
+	// there are no real channels while capturing
+	return true
+}
+
+func captureRate(channel SubSample, rate float64) bool {
+	return captureLoop(channel, func(state *loopState) {
+		speed := rate / state.sample.getFrequency()
+
+		if math.Abs(speed-state.speed) < 0.0001 {
+			return
+		}
+
+		if state.event >= 0 {
+			state.pause()
+			state.speed = speed
+			state.resume()
+		} else {
+			state.speed = speed
+		}
+	})
+}
+
+func captureStop(channel SubSample) bool {
+	return captureLoop(channel, func(state *loopState) {
+		state.pause()
+		delete(captureLoops, channel)
+	})
+}
+
+func capturePause(channel SubSample) bool {
+	return captureLoop(channel, func(state *loopState) {
+		state.pause()
+	})
+}
+
+func captureResume(channel SubSample) bool {
+	return captureLoop(channel, func(state *loopState) {
+		state.resume()
+	})
+}
+
+func (state *loopState) pause() {
+	if state.event < 0 {
+		return
+	}
+
+	event := &captured[state.event]
+	event.End = captureTime
+
+	// playback continues from the same place after resuming or changing the rate
+	state.offset += (event.End - event.Time) / 1000 * event.Speed
+	state.event = -1
+}
+
+func (state *loopState) resume() {
+	if state.event >= 0 {
+		return
+	}
+
+	state.event = len(captured)
+
+	captured = append(captured, SampleEvent{
+		Time:    captureTime,
+		End:     captureTime,
+		Sample:  state.sample,
+		Volume:  state.volume,
+		Balance: state.balance,
+		Loop:    true,
+		Speed:   state.speed,
+		Offset:  state.offset,
+	})
+}
+
+func (wv *Sample) getFrequency() float64 {
+	var info C.BASS_SAMPLE
+	if C.BASS_SampleGetInfo(C.DWORD(wv.channel), &info) == 0 || info.freq == 0 {
+		return MixFrequency
+	}
+
+	return float64(info.freq)
+}
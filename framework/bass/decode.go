@@ -0,0 +1,131 @@
+package bass
+
+/*
+#include "bass_util.h"
+#include "bass.h"
+#include "bass_fx.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// MixFrequency is the sample rate of decoded audio.
+const MixFrequency = 44100
+
+// Decode returns sample's audio as interleaved stereo float samples at MixFrequency, or nil if the sample failed to load.
+func (wv *Sample) Decode() []float32 {
+	var info C.BASS_SAMPLE
+	if C.BASS_SampleGetInfo(C.DWORD(wv.channel), &info) == 0 || info.length == 0 {
+		return nil
+	}
+
+	raw := make([]byte, int(info.length))
+	if C.BASS_SampleGetData(C.DWORD(wv.channel), unsafe.Pointer(&raw[0])) == 0 {
+		return nil
+	}
+
+	var data []float32
+
+	switch {
+	case info.flags&C.BASS_SAMPLE_FLOAT > 0:
+		data = make([]float32, len(raw)/4)
+		for i := range data {
+			data[i] = math.Float32frombits(uint32(raw[i*4]) | uint32(raw[i*4+1])<<8 | uint32(raw[i*4+2])<<16 | uint32(raw[i*4+3])<<24)
+		}
+	case info.flags&C.BASS_SAMPLE_8BITS > 0:
+		data = make([]float32, len(raw))
+		for i := range data {
+			data[i] = (float32(raw[i]) - 128) / 128
+		}
+	default:
+		data = make([]float32, len(raw)/2)
+		for i := range data {
+			data[i] = float32(int16(uint16(raw[i*2])|uint16(raw[i*2+1])<<8)) / 32768
+		}
+	}
+
+	return toMixFormat(data, int(info.chans), int(info.freq))
+}
+
+// DecodeTrack decodes the whole music file from position (in seconds) with given tempo and pitch,
+// the result is interleaved stereo float samples at MixFrequency.
+func DecodeTrack(path string, position, tempo, pitch float64) ([]float32, error) {
+	source := C.CreateBassStream(C.CString(path), C.BASS_STREAM_DECODE|C.BASS_STREAM_PRESCAN|C.BASS_SAMPLE_FLOAT)
+	if source == 0 {
+		return nil, fmt.Errorf("failed to open %s, error: %d", path, int(C.BASS_ErrorGetCode()))
+	}
+
+	channel := C.BASS_FX_TempoCreate(source, C.BASS_FX_FREESOURCE|C.BASS_STREAM_DECODE)
+	if channel == 0 {
+		C.BASS_StreamFree(source)
+		return nil, fmt.Errorf("failed to create tempo stream for %s, error: %d", path, int(C.BASS_ErrorGetCode()))
+	}
+
+	defer C.BASS_StreamFree(channel)
+
+	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_TEMPO, C.float((tempo-1.0)*100))
+	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_TEMPO_PITCH, C.float((pitch-1.0)*12))
+	C.BASS_ChannelSetPosition(channel, C.BASS_ChannelSeconds2Bytes(channel, C.double(position)), C.BASS_POS_BYTE)
+
+	var info C.BASS_CHANNELINFO
+	C.BASS_ChannelGetInfo(channel, &info)
+
+	var data []float32
+
+	buffer := make([]float32, 16384)
+
+	for {
+		read := C.BASS_ChannelGetData(channel, unsafe.Pointer(&buffer[0]), C.DWORD(len(buffer)*4)|C.BASS_DATA_FLOAT)
+		if read == C.DWORD(math.MaxUint32) || read == 0 {
+			break
+		}
+
+		data = append(data, buffer[:int(read)/4]...)
+	}
+
+	return toMixFormat(data, int(info.chans), int(info.freq)), nil
+}
+
+// toMixFormat converts interleaved samples to stereo at MixFrequency, channels above 2 are dropped.
+func toMixFormat(data []float32, channels, frequency int) []float32 {
+	if channels < 1 || frequency < 1 {
+		return nil
+	}
+
+	frames := len(data) / channels
+
+	ratio := float64(frequency) / MixFrequency
+	outFrames := int(float64(frames) / ratio)
+
+	result := make([]float32, outFrames*2)
+
+	for i := 0; i < outFrames; i++ {
+		pos := float64(i) * ratio
+
+		index := int(pos)
+		t := float32(pos - float64(index))
+
+		next := index + 1
+		if next >= frames {
+			next = frames - 1
+		}
+
+		for c := 0; c < 2; c++ {
+			source := c
+			if source >= channels {
+				source = channels - 1
+			}
+
+			a := data[index*channels+source]
+			b := data[next*channels+source]
+
+			result[i*2+c] = a + (b-a)*t
+		}
+	}
+
+	return result
+}
@@ -40,74 +40,83 @@ func NewSampleData(data []byte) *Sample {
 }
 
 func (wv *Sample) Play() SubSample {
-	channel := C.BASS_SampleGetChannel(C.DWORD(wv.channel), 0)
-	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume))
-	C.BASS_ChannelPlay(channel, 1)
-
-	return SubSample(channel)
+	return wv.play(settings.Audio.GeneralVolume*settings.Audio.SampleVolume, 0, false)
 }
 
 func (wv *Sample) PlayLoop() SubSample {
-	sSample := wv.Play()
-	C.BASS_ChannelFlags(C.HCHANNEL(sSample), C.BASS_SAMPLE_LOOP, C.BASS_SAMPLE_LOOP)
-	return sSample
+	return wv.play(settings.Audio.GeneralVolume*settings.Audio.SampleVolume, 0, true)
 }
 
 func (wv *Sample) PlayV(volume float64) SubSample {
-	channel := C.BASS_SampleGetChannel(C.DWORD(wv.channel), 0)
-	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(volume))
-	C.BASS_ChannelPlay(channel, 1)
-
-	return SubSample(channel)
+	return wv.play(volume, 0, false)
 }
 
 func (wv *Sample) PlayVLoop(volume float64) SubSample {
-	sSample := wv.PlayV(volume)
-	C.BASS_ChannelFlags(C.HCHANNEL(sSample), C.BASS_SAMPLE_LOOP, C.BASS_SAMPLE_LOOP)
-	return sSample
+	return wv.play(volume, 0, true)
 }
 
 func (wv *Sample) PlayRV(volume float64) SubSample {
-	channel := C.BASS_SampleGetChannel(C.DWORD(wv.channel), 0)
-	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume))
-	C.BASS_ChannelPlay(channel, 1)
-
-	return SubSample(channel)
+	return wv.play(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume, 0, false)
 }
 
 func (wv *Sample) PlayRVLoop(volume float64) SubSample {
-	sSample := wv.PlayRV(volume)
-	C.BASS_ChannelFlags(C.HCHANNEL(sSample), C.BASS_SAMPLE_LOOP, C.BASS_SAMPLE_LOOP)
-	return sSample
+	return wv.play(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume, 0, true)
 }
 
 func (wv *Sample) PlayRVPos(volume float64, balance float64) SubSample {
+	return wv.play(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume, balance, false)
+}
+
+func (wv *Sample) PlayRVPosLoop(volume float64, balance float64) SubSample {
+	return wv.play(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume, balance, true)
+}
+
+func (wv *Sample) play(volume, balance float64, loop bool) SubSample {
+	if handle, ok := capture(wv, volume, balance, loop); ok {
+		return handle
+	}
+
 	channel := C.BASS_SampleGetChannel(C.DWORD(wv.channel), 0)
-	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume))
+	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(volume))
 	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_PAN, C.float(balance))
+
+	if loop {
+		C.BASS_ChannelFlags(channel, C.BASS_SAMPLE_LOOP, C.BASS_SAMPLE_LOOP)
+	}
+
 	C.BASS_ChannelPlay(channel, 1)
 
 	return SubSample(channel)
 }
 
-func (wv *Sample) PlayRVPosLoop(volume float64, balance float64) SubSample {
-	sSample := wv.PlayRVPos(volume, balance)
-	C.BASS_ChannelFlags(C.HCHANNEL(sSample), C.BASS_SAMPLE_LOOP, C.BASS_SAMPLE_LOOP)
-	return sSample
-}
-
 func SetRate(channel SubSample, rate float64) {
+	if captureRate(channel, rate) {
+		return
+	}
+
 	C.BASS_ChannelSetAttribute(C.HCHANNEL(channel), C.BASS_ATTRIB_FREQ, C.float(rate))
 }
 
 func StopSample(channel SubSample) {
+	if captureStop(channel) {
+		return
+	}
+
 	C.BASS_ChannelStop(C.HCHANNEL(channel))
 }
 
 func PauseSample(channel SubSample) {
+	if capturePause(channel) {
+		return
+	}
+
 	C.BASS_ChannelPause(C.HCHANNEL(channel))
 }
 
 func PlaySample(channel SubSample) {
+	if captureResume(channel) {
+		return
+	}
+
 	C.BASS_ChannelPlay(C.HCHANNEL(channel), 0)
 }
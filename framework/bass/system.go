@@ -15,7 +15,9 @@ import (
 	"runtime"
 )
 
-func Init() {
+// Init initializes BASS on the default output device. In offline mode, or when there's no working device,
+// "no sound" device is used, which still allows decoding and capturing samples.
+func Init(offline bool) {
 	playbackBufferLength := 500
 	deviceBufferLength := 10
 	updatePeriod := 5
@@ -45,13 +47,26 @@ func Init() {
 	// BASS_CONFIG_MP3_OLDGAPS
 	C.BASS_SetConfig(C.DWORD(68), C.DWORD(1))
 
-	if C.BASS_Init(C.int(-1), C.DWORD(44100), C.DWORD(0), nil, nil) != 0 {
-		log.Println("BASS Initialized!")
-		log.Println("BASS Version:", parseVersion(int(C.BASS_GetVersion())))
-		log.Println("BASS FX Version:", parseVersion(int(C.BASS_FX_GetVersion())))
-	} else {
-		panic(fmt.Sprintf("Failed to run BASS, error: %d", int(C.BASS_ErrorGetCode())))
+	device := -1
+	if offline {
+		device = 0
 	}
+
+	if C.BASS_Init(C.int(device), C.DWORD(44100), C.DWORD(0), nil, nil) == 0 {
+		if device == 0 {
+			panic(fmt.Sprintf("Failed to run BASS, error: %d", int(C.BASS_ErrorGetCode())))
+		}
+
+		log.Println(fmt.Sprintf("Failed to open the audio device, error: %d. Continuing without sound output", int(C.BASS_ErrorGetCode())))
+
+		if C.BASS_Init(C.int(0), C.DWORD(44100), C.DWORD(0), nil, nil) == 0 {
+			panic(fmt.Sprintf("Failed to run BASS, error: %d", int(C.BASS_ErrorGetCode())))
+		}
+	}
+
+	log.Println("BASS Initialized!")
+	log.Println("BASS Version:", parseVersion(int(C.BASS_GetVersion())))
+	log.Println("BASS FX Version:", parseVersion(int(C.BASS_FX_GetVersion())))
 }
 
 func parseVersion(version int) string {
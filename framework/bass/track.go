@@ -28,15 +28,33 @@ type Track struct {
 	leftChannel  float64
 	rightChannel float64
 	lowMax       float64
+	decode       bool
 }
 
 func NewTrack(path string) *Track {
+	return newTrack(path, false)
+}
+
+// NewTrackDecode creates a Track which doesn't output to the audio device. It's used when recording:
+// position has to be set manually and Update analyses audio at that position without moving it.
+func NewTrackDecode(path string) *Track {
+	return newTrack(path, true)
+}
+
+func newTrack(path string, decode bool) *Track {
 	player := new(Track)
 
 	channel := C.CreateBassStream(C.CString(path), C.BASS_ASYNCFILE|C.BASS_STREAM_DECODE|C.BASS_STREAM_PRESCAN)
 
-	player.channel = C.BASS_FX_TempoCreate(channel, C.BASS_FX_FREESOURCE)
+	flags := C.DWORD(C.BASS_FX_FREESOURCE)
+	if decode {
+		flags |= C.BASS_STREAM_DECODE
+	}
+
+	player.channel = C.BASS_FX_TempoCreate(channel, flags)
 	player.fft = make([]float32, 512)
+	player.decode = decode
+
 	return player
 }
 
@@ -95,6 +113,12 @@ func (wv *Track) GetState() int {
 }
 
 func (wv *Track) Update() {
+	var position C.QWORD
+	if wv.decode {
+		// reading data from a decoding channel moves its position
+		position = C.BASS_ChannelGetPosition(wv.channel, C.BASS_POS_BYTE)
+	}
+
 	C.BASS_ChannelGetData(wv.channel, unsafe.Pointer(&wv.fft[0]), C.BASS_DATA_FFT1024)
 
 	toPeak := 0.0
@@ -127,6 +151,10 @@ func (wv *Track) Update() {
 
 	wv.leftChannel = float64(left) / 32768
 	wv.rightChannel = float64(right) / 32768
+
+	if wv.decode {
+		C.BASS_ChannelSetPosition(wv.channel, position, C.BASS_POS_BYTE)
+	}
 }
 
 func (wv *Track) GetFFT() []float32 {
@@ -3,7 +3,6 @@ package batch
 import (
 	"fmt"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/wieku/danser-go/framework/assets"
 	"github.com/wieku/danser-go/framework/graphics/attribute"
 	"github.com/wieku/danser-go/framework/graphics/blend"
 	"github.com/wieku/danser-go/framework/graphics/buffer"
@@ -50,17 +49,7 @@ func NewQuadBatchSize(maxSprites int) *QuadBatch {
 		panic(fmt.Sprintf("QuadBatch size is too big, maximum quads allowed: 10922, given: %d", maxSprites))
 	}
 
-	vert, err := assets.GetString("assets/shaders/sprite.vsh")
-	if err != nil {
-		panic(err)
-	}
-
-	frag, err := assets.GetString("assets/shaders/sprite.fsh")
-	if err != nil {
-		panic(err)
-	}
-
-	rShader := shader.NewRShader(shader.NewSource(string(vert), shader.Vertex), shader.NewSource(string(frag), shader.Fragment))
+	rShader := shader.LoadRShader("assets/shaders/sprite.vsh", "assets/shaders/sprite.fsh")
 
 	vao := buffer.NewVertexArrayObject()
 
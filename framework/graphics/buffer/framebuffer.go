@@ -24,9 +24,14 @@ type Framebuffer struct {
 
 // NewFrame creates a new fully transparent Framebuffer with given dimensions in pixels.
 func NewFrame(width, height int, smooth, depth bool) *Framebuffer {
+	return NewFrameFormat(width, height, texture.RGBA, smooth, depth)
+}
+
+// NewFrameFormat creates a new Framebuffer with given texture format, float formats allow values outside of 0-1 range.
+func NewFrameFormat(width, height int, format texture.Format, smooth, depth bool) *Framebuffer {
 	f := new(Framebuffer)
 
-	f.tex = texture.NewTextureSingle(width, height, 0)
+	f.tex = texture.NewTextureSingleFormat(width, height, format, 0)
 
 	gl.GenFramebuffers(1, &f.obj)
 
@@ -166,3 +171,20 @@ func (f *Framebuffer) Unbind() {
 func (f *Framebuffer) Texture() texture.Texture {
 	return f.tex
 }
+
+// ReadPixels copies the Framebuffer's content into data as RGBA bytes, starting with the bottom row.
+// data has to hold at least width*height*4 bytes.
+func (f *Framebuffer) ReadPixels(data []uint8) {
+	handle := f.obj
+	if f.multisampled {
+		handle = f.helperObj
+	}
+
+	history.Push(gl.FRAMEBUFFER_BINDING)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, handle)
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, f.tex.GetWidth(), f.tex.GetHeight(), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(data))
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, history.Pop(gl.FRAMEBUFFER_BINDING))
+}
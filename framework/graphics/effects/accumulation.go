@@ -0,0 +1,96 @@
+package effects
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/wieku/danser-go/framework/graphics/attribute"
+	"github.com/wieku/danser-go/framework/graphics/blend"
+	"github.com/wieku/danser-go/framework/graphics/buffer"
+	"github.com/wieku/danser-go/framework/graphics/shader"
+	"github.com/wieku/danser-go/framework/graphics/texture"
+)
+
+// AccumulationEffect sums weighted frames in a float framebuffer, so they can be blended without losing precision.
+type AccumulationEffect struct {
+	passShader *shader.RShader
+	fbo        *buffer.Framebuffer
+	vao        *buffer.VertexArrayObject
+}
+
+func NewAccumulationEffect(width, height int) *AccumulationEffect {
+	effect := new(AccumulationEffect)
+
+	effect.passShader = shader.LoadRShader("assets/shaders/fbopass.vsh", "assets/shaders/fbopass.fsh")
+
+	effect.vao = buffer.NewVertexArrayObject()
+
+	effect.vao.AddVBO("default", 6, 0, attribute.Format{
+		{Name: "in_position", Type: attribute.Vec3},
+		{Name: "in_tex_coord", Type: attribute.Vec2},
+	})
+
+	effect.vao.SetData("default", 0, []float32{
+		-1, -1, 0, 0, 0,
+		1, -1, 0, 1, 0,
+		-1, 1, 0, 0, 1,
+		1, -1, 0, 1, 0,
+		1, 1, 0, 1, 1,
+		-1, 1, 0, 0, 1,
+	})
+
+	effect.vao.Bind()
+	effect.vao.Attach(effect.passShader)
+	effect.vao.Unbind()
+
+	effect.fbo = buffer.NewFrameFormat(width, height, texture.RGBA32F, true, false)
+
+	return effect
+}
+
+// Clear removes all accumulated frames.
+func (effect *AccumulationEffect) Clear() {
+	effect.fbo.Bind()
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	effect.fbo.Unbind()
+}
+
+// Add adds the texture multiplied by weight to accumulated frames. Weights of all added frames should sum up to 1.
+func (effect *AccumulationEffect) Add(tex texture.Texture, weight float64) {
+	effect.fbo.Bind()
+
+	blend.Push()
+	blend.Enable()
+	blend.SetEquation(blend.Add)
+	blend.SetFunction(blend.ConstantColor, blend.One)
+	blend.SetColor(mgl32.Vec4{float32(weight), float32(weight), float32(weight), float32(weight)})
+
+	effect.draw(tex)
+
+	blend.Pop()
+
+	effect.fbo.Unbind()
+}
+
+// Render draws accumulated frames to the currently bound framebuffer, replacing its content.
+func (effect *AccumulationEffect) Render() {
+	blend.Push()
+	blend.Disable()
+
+	effect.draw(effect.fbo.Texture())
+
+	blend.Pop()
+}
+
+func (effect *AccumulationEffect) draw(tex texture.Texture) {
+	effect.passShader.Bind()
+	effect.passShader.SetUniform("tex", int32(0))
+
+	tex.Bind(0)
+
+	effect.vao.Bind()
+	effect.vao.Draw()
+	effect.vao.Unbind()
+
+	effect.passShader.Unbind()
+}
@@ -2,7 +2,6 @@ package effects
 
 import (
 	"github.com/go-gl/gl/v3.3-core/gl"
-	"github.com/wieku/danser-go/framework/assets"
 	"github.com/wieku/danser-go/framework/graphics/attribute"
 	"github.com/wieku/danser-go/framework/graphics/blend"
 	"github.com/wieku/danser-go/framework/graphics/buffer"
@@ -23,24 +22,8 @@ type BloomEffect struct {
 func NewBloomEffect(width, height int) *BloomEffect {
 	effect := new(BloomEffect)
 
-	vert, err := assets.GetString("assets/shaders/fbopass.vsh")
-	if err != nil {
-		panic(err)
-	}
-
-	frag, err := assets.GetString("assets/shaders/brightfilter.fsh")
-	if err != nil {
-		panic(err)
-	}
-
-	effect.filterShader = shader.NewRShader(shader.NewSource(string(vert), shader.Vertex), shader.NewSource(string(frag), shader.Fragment))
-
-	frag, err = assets.GetString("assets/shaders/combine.fsh")
-	if err != nil {
-		panic(err)
-	}
-
-	effect.combineShader = shader.NewRShader(shader.NewSource(string(vert), shader.Vertex), shader.NewSource(string(frag), shader.Fragment))
+	effect.filterShader = shader.LoadRShader("assets/shaders/fbopass.vsh", "assets/shaders/brightfilter.fsh")
+	effect.combineShader = shader.LoadRShader("assets/shaders/fbopass.vsh", "assets/shaders/combine.fsh")
 
 	effect.vao = buffer.NewVertexArrayObject()
 
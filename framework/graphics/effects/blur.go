@@ -4,7 +4,6 @@ import (
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/wieku/danser-go/app/settings"
-	"github.com/wieku/danser-go/framework/assets"
 	"github.com/wieku/danser-go/framework/graphics/attribute"
 	"github.com/wieku/danser-go/framework/graphics/buffer"
 	"github.com/wieku/danser-go/framework/graphics/shader"
@@ -27,17 +26,7 @@ func NewBlurEffect(width, height int) *BlurEffect {
 	effect.size = mgl32.Vec2{float32(width), float32(height)}
 	effect.SetBlur(0, 0)
 
-	vert, err := assets.GetString("assets/shaders/fbopass.vsh")
-	if err != nil {
-		panic(err)
-	}
-
-	frag, err := assets.GetString("assets/shaders/blur.fsh")
-	if err != nil {
-		panic(err)
-	}
-
-	effect.blurShader = shader.NewRShader(shader.NewSource(string(vert), shader.Vertex), shader.NewSource(string(frag), shader.Fragment))
+	effect.blurShader = shader.LoadRShader("assets/shaders/fbopass.vsh", "assets/shaders/blur.fsh")
 
 	effect.vao = buffer.NewVertexArrayObject()
 
@@ -0,0 +1,208 @@
+package effects
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/wieku/danser-go/framework/assets"
+	"github.com/wieku/danser-go/framework/graphics/attribute"
+	"github.com/wieku/danser-go/framework/graphics/blend"
+	"github.com/wieku/danser-go/framework/graphics/buffer"
+	"github.com/wieku/danser-go/framework/graphics/shader"
+	"io/ioutil"
+	"log"
+)
+
+type postProcessPass struct {
+	shader   *shader.RShader
+	vao      *buffer.VertexArrayObject
+	uniforms map[string][]float64
+}
+
+// PostProcessEffect runs the frame through a chain of fragment shaders, each pass reads the result of the previous one.
+// Shaders get the frame in "tex" (sampler2DArray, layer 0) and texture coordinates in "tex_coord" from fbopass.vsh,
+// "resolution" (vec2) is set automatically.
+type PostProcessEffect struct {
+	passes []*postProcessPass
+	fbos   [2]*buffer.Framebuffer
+
+	vertex   string
+	uniforms map[string][]float64
+}
+
+func NewPostProcessEffect(width, height int) *PostProcessEffect {
+	effect := new(PostProcessEffect)
+
+	vert, err := assets.GetString("assets/shaders/fbopass.vsh")
+	if err != nil {
+		panic(err)
+	}
+
+	effect.vertex = vert
+
+	effect.fbos[0] = buffer.NewFrame(width, height, true, false)
+	effect.fbos[1] = buffer.NewFrame(width, height, true, false)
+
+	effect.uniforms = make(map[string][]float64)
+	effect.SetUniform("resolution", float64(width), float64(height))
+
+	return effect
+}
+
+// AddPass reads the fragment shader from disk (#include directives are allowed), compiles it and appends it to the chain.
+// Uniforms hold values of the shader's own uniforms, they take precedence over ones set with SetUniform.
+// If the shader fails to compile, the chain stays unchanged and the error is returned.
+func (effect *PostProcessEffect) AddPass(file string, uniforms map[string][]float64) error {
+	source, files, err := shader.Preprocess(file, readFile)
+	if err != nil {
+		return err
+	}
+
+	program, err := shader.BuildRShader(shader.NewSourceNamed(effect.vertex, shader.Vertex, "fbopass.vsh"), shader.NewSourceNamed(source, shader.Fragment, files...))
+	if err != nil {
+		return err
+	}
+
+	if !program.HasUniform("tex") {
+		program.Dispose()
+		return fmt.Errorf("%s: shader doesn't read the frame from \"tex\" uniform", file)
+	}
+
+	for name := range uniforms {
+		if !program.HasUniform(name) {
+			log.Println(fmt.Sprintf("%s: uniform \"%s\" is not used by the shader, skipping", file, name))
+		}
+	}
+
+	pass := &postProcessPass{
+		shader:   program,
+		uniforms: uniforms,
+	}
+
+	pass.vao = buffer.NewVertexArrayObject()
+
+	pass.vao.AddVBO("default", 6, 0, attribute.Format{
+		{Name: "in_position", Type: attribute.Vec3},
+		{Name: "in_tex_coord", Type: attribute.Vec2},
+	})
+
+	pass.vao.SetData("default", 0, []float32{
+		-1, -1, 0, 0, 0,
+		1, -1, 0, 1, 0,
+		-1, 1, 0, 0, 1,
+		1, -1, 0, 1, 0,
+		1, 1, 0, 1, 1,
+		-1, 1, 0, 0, 1,
+	})
+
+	pass.vao.Bind()
+	pass.vao.Attach(program)
+	pass.vao.Unbind()
+
+	effect.passes = append(effect.passes, pass)
+
+	return nil
+}
+
+// GetPasses returns the number of successfully compiled passes.
+func (effect *PostProcessEffect) GetPasses() int {
+	return len(effect.passes)
+}
+
+// SetUniform sets the value of a uniform in all passes that use it. One value sets float, int and bool uniforms,
+// 2 to 4 values set vectors.
+func (effect *PostProcessEffect) SetUniform(name string, values ...float64) {
+	effect.uniforms[name] = values
+}
+
+// Begin redirects drawing to the chain. Without passes it does nothing, so the frame is drawn directly.
+func (effect *PostProcessEffect) Begin() {
+	if len(effect.passes) == 0 {
+		return
+	}
+
+	effect.fbos[0].Bind()
+	gl.ClearColor(0, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+}
+
+// EndAndRender runs all passes, the last one draws to the framebuffer that was bound before Begin.
+func (effect *PostProcessEffect) EndAndRender() {
+	if len(effect.passes) == 0 {
+		return
+	}
+
+	effect.fbos[0].Unbind()
+
+	blend.Push()
+	blend.Disable()
+
+	source := 0
+
+	for i, pass := range effect.passes {
+		last := i == len(effect.passes)-1
+
+		if !last {
+			effect.fbos[1-source].Bind()
+		}
+
+		pass.shader.Bind()
+		pass.shader.SetUniform("tex", int32(0))
+
+		for name, values := range effect.uniforms {
+			if _, ok := pass.uniforms[name]; !ok {
+				setPassUniform(pass.shader, name, values)
+			}
+		}
+
+		for name, values := range pass.uniforms {
+			setPassUniform(pass.shader, name, values)
+		}
+
+		effect.fbos[source].Texture().Bind(0)
+
+		pass.vao.Bind()
+		pass.vao.Draw()
+		pass.vao.Unbind()
+
+		pass.shader.Unbind()
+
+		if !last {
+			effect.fbos[1-source].Unbind()
+			source = 1 - source
+		}
+	}
+
+	blend.Pop()
+}
+
+func readFile(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	return string(data), err
+}
+
+func setPassUniform(program *shader.RShader, name string, values []float64) {
+	if name == "tex" || !program.HasUniform(name) {
+		return
+	}
+
+	var v [4]float32
+	for i := 0; i < len(values) && i < len(v); i++ {
+		v[i] = float32(values[i])
+	}
+
+	switch program.GetUnformInfo(name).Type {
+	case attribute.Float:
+		program.SetUniform(name, v[0])
+	case attribute.Vec2:
+		program.SetUniform(name, mgl32.Vec2{v[0], v[1]})
+	case attribute.Vec3:
+		program.SetUniform(name, mgl32.Vec3{v[0], v[1], v[2]})
+	case attribute.Vec4:
+		program.SetUniform(name, mgl32.Vec4(v))
+	case attribute.Mat2, attribute.Mat23, attribute.Mat24, attribute.Mat3, attribute.Mat32, attribute.Mat34, attribute.Mat4, attribute.Mat42, attribute.Mat43:
+		// matrices can't be set from the chain
+	default:
+		program.SetUniform(name, int32(v[0]))
+	}
+}
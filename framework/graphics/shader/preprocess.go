@@ -0,0 +1,81 @@
+package shader
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var includeDirective = regexp.MustCompile(`^\s*#include\s+"([^"]+)"\s*(//.*)?$`)
+
+// Preprocess reads the shader file and resolves `#include "file"` directives, paths are relative to the including file.
+// Every file is included only once, so include cycles are harmless. #line directives are inserted to keep line numbers
+// in compile errors. Returns the source and all files used, index of a file is its GLSL source string number.
+func Preprocess(file string, read func(file string) (string, error)) (string, []string, error) {
+	processor := &preprocessor{
+		read:     read,
+		included: make(map[string]bool),
+	}
+
+	var builder strings.Builder
+
+	if err := processor.process(&builder, filepath.ToSlash(file)); err != nil {
+		return "", nil, err
+	}
+
+	return builder.String(), processor.files, nil
+}
+
+type preprocessor struct {
+	read     func(file string) (string, error)
+	included map[string]bool
+	files    []string
+}
+
+func (processor *preprocessor) process(builder *strings.Builder, file string) error {
+	file = path.Clean(file)
+
+	if processor.included[file] {
+		return nil
+	}
+
+	processor.included[file] = true
+
+	source, err := processor.read(file)
+	if err != nil {
+		return err
+	}
+
+	index := len(processor.files)
+	processor.files = append(processor.files, file)
+
+	if index > 0 {
+		// GLSL 3.30 starts counting from the next line
+		builder.WriteString(fmt.Sprintf("#line 0 %d\n", index))
+	}
+
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for i, line := range lines {
+		match := includeDirective.FindStringSubmatch(line)
+		if match == nil {
+			builder.WriteString(line)
+			builder.WriteString("\n")
+
+			continue
+		}
+
+		if err = processor.process(builder, path.Join(path.Dir(file), match[1])); err != nil {
+			return fmt.Errorf("%s:%d: %w", file, i+1, err)
+		}
+
+		builder.WriteString(fmt.Sprintf("#line %d %d\n", i+1, index))
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package shader
+
+import (
+	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// loadedShader is a program created by LoadRShader, kept to rebuild it when its files change.
+type loadedShader struct {
+	shader *RShader
+	paths  []string
+	files  []string
+}
+
+var hotReload bool
+var loadedShaders []*loadedShader
+var modTimes = make(map[string]time.Time)
+var reloadMutex sync.Mutex
+
+// EnableHotReload makes LoadRShader track loaded programs and starts watching their files on disk.
+// Changed programs are rebuilt on the main thread and swapped in if they compile, errors are logged.
+// It should be called before shaders are loaded and only when assets are read from disk.
+func EnableHotReload() {
+	if hotReload {
+		return
+	}
+
+	hotReload = true
+
+	go func() {
+		for {
+			time.Sleep(500 * time.Millisecond)
+
+			if changed := findChanged(); len(changed) > 0 {
+				mainthread.CallNonBlock(func() {
+					for _, loaded := range changed {
+						loaded.reload()
+					}
+				})
+			}
+		}
+	}()
+
+	log.Println("Shader hot reload enabled")
+}
+
+// LoadRShader loads sources from assets (see LoadSource) and builds the program, panics on errors like NewRShader.
+func LoadRShader(paths ...string) *RShader {
+	sources, files, err := loadSources(paths)
+	if err != nil {
+		panic(err)
+	}
+
+	s, err := BuildRShader(sources...)
+	if err != nil {
+		panic(err)
+	}
+
+	if hotReload {
+		reloadMutex.Lock()
+
+		loadedShaders = append(loadedShaders, &loadedShader{
+			shader: s,
+			paths:  paths,
+			files:  files,
+		})
+
+		for _, file := range files {
+			if _, ok := modTimes[file]; !ok {
+				modTimes[file] = getModTime(file)
+			}
+		}
+
+		reloadMutex.Unlock()
+	}
+
+	return s
+}
+
+func loadSources(paths []string) ([]*Source, []string, error) {
+	sources := make([]*Source, 0, len(paths))
+	files := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		src, err := LoadSource(path)
+		if err != nil {
+			for _, src := range sources {
+				src.Dispose()
+			}
+
+			return nil, nil, err
+		}
+
+		sources = append(sources, src)
+		files = append(files, src.files...)
+	}
+
+	return sources, files, nil
+}
+
+func findChanged() (changed []*loadedShader) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	changedFiles := make(map[string]bool)
+
+	for file, modTime := range modTimes {
+		if newTime := getModTime(file); !newTime.Equal(modTime) {
+			modTimes[file] = newTime
+			changedFiles[file] = true
+		}
+	}
+
+	if len(changedFiles) == 0 {
+		return
+	}
+
+	for _, loaded := range loadedShaders {
+		for _, file := range loaded.files {
+			if changedFiles[file] {
+				changed = append(changed, loaded)
+				break
+			}
+		}
+	}
+
+	return
+}
+
+func getModTime(file string) time.Time {
+	stat, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return stat.ModTime()
+}
+
+// reload rebuilds the program from its files, on failure the old one stays in use.
+func (loaded *loadedShader) reload() {
+	s := loaded.shader
+	name := strings.Join(loaded.paths, ", ")
+
+	if s.disposed {
+		return
+	}
+
+	sources, files, err := loadSources(loaded.paths)
+	if err == nil {
+		oldHandle := s.handle
+		oldUniforms := s.uniforms
+
+		if err = s.link(sources, s.attributes); err == nil {
+			gl.DeleteProgram(oldHandle)
+
+			// location -1 is ignored by glUniform*, callers can keep setting uniforms that got optimized out
+			for uName, uniform := range oldUniforms {
+				if _, ok := s.uniforms[uName]; !ok {
+					uniform.Location = -1
+					s.uniforms[uName] = uniform
+				}
+			}
+		}
+	}
+
+	if err != nil {
+		log.Println("Failed to reload", name+":", err)
+		return
+	}
+
+	reloadMutex.Lock()
+
+	// includes may have changed
+	loaded.files = files
+
+	for _, file := range files {
+		if _, ok := modTimes[file]; !ok {
+			modTimes[file] = getModTime(file)
+		}
+	}
+
+	reloadMutex.Unlock()
+
+	log.Println("Reloaded", name)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/wieku/danser-go/framework/graphics/history"
 	"github.com/wieku/danser-go/framework/math/color"
 	"runtime"
+	"strings"
 )
 
 type RShader struct {
@@ -28,40 +29,77 @@ func NewRShader(sources ...*Source) *RShader {
 		}
 	}
 
+	s, err := BuildRShader(sources...)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// BuildRShader links the sources into a program like NewRShader, but returns compile and link errors instead of panicking.
+// Sources are disposed either way.
+func BuildRShader(sources ...*Source) (*RShader, error) {
 	s := new(RShader)
-	s.attributes = make(map[string]attribute.VertexAttribute)
-	s.uniforms = make(map[string]attribute.VertexAttribute)
 
-	s.handle = gl.CreateProgram()
+	if err := s.link(sources, nil); err != nil {
+		return nil, err
+	}
+
+	runtime.SetFinalizer(s, (*RShader).Dispose)
+
+	return s, nil
+}
+
+// link builds a new program from the sources and switches s to it if it succeeds. Attributes found in locations are
+// bound to the same locations, so VAOs attached to the previous program keep working.
+func (s *RShader) link(sources []*Source, locations map[string]attribute.VertexAttribute) error {
+	defer func() {
+		for _, src := range sources {
+			src.Dispose()
+		}
+	}()
 
 	for _, src := range sources {
-		gl.AttachShader(s.handle, src.handle)
+		if err := src.Err(); err != nil {
+			return err
+		}
 	}
 
-	gl.LinkProgram(s.handle)
+	handle := gl.CreateProgram()
+
+	for _, src := range sources {
+		gl.AttachShader(handle, src.handle)
+	}
+
+	for name, attr := range locations {
+		gl.BindAttribLocation(handle, uint32(attr.Location), gl.Str(name+"\x00"))
+	}
+
+	gl.LinkProgram(handle)
 
 	var success int32
-	gl.GetProgramiv(s.handle, gl.LINK_STATUS, &success)
+	gl.GetProgramiv(handle, gl.LINK_STATUS, &success)
 	if success == gl.FALSE {
 		var logLen int32
-		gl.GetProgramiv(s.handle, gl.INFO_LOG_LENGTH, &logLen)
+		gl.GetProgramiv(handle, gl.INFO_LOG_LENGTH, &logLen)
 
 		infoLog := make([]byte, logLen)
-		gl.GetProgramInfoLog(s.handle, logLen, nil, &infoLog[0])
+		gl.GetProgramInfoLog(handle, logLen, nil, &infoLog[0])
 
-		panic(fmt.Sprintf("Can't link shader program: %s", string(infoLog)))
+		gl.DeleteProgram(handle)
+
+		return fmt.Errorf("can't link shader program: %s", strings.TrimRight(string(infoLog), "\x00"))
 	}
 
+	s.handle = handle
+	s.attributes = make(map[string]attribute.VertexAttribute)
+	s.uniforms = make(map[string]attribute.VertexAttribute)
+
 	s.fetchAttributes()
 	s.fetchUniforms()
 
-	for _, src := range sources {
-		src.Dispose()
-	}
-
-	runtime.SetFinalizer(s, (*RShader).Dispose)
-
-	return s
+	return nil
 }
 
 func (s *RShader) fetchAttributes() {
@@ -138,6 +176,12 @@ func (s *RShader) GetUnformInfo(name string) attribute.VertexAttribute {
 	return attr
 }
 
+// HasUniform returns whether the program uses the uniform, unused uniforms are removed by the GLSL compiler.
+func (s *RShader) HasUniform(name string) bool {
+	_, exists := s.uniforms[name]
+	return exists
+}
+
 func (s *RShader) SetUniform(name string, value interface{}) {
 	uniform, exists := s.uniforms[name]
 	if !exists {
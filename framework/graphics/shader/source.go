@@ -1,8 +1,14 @@
 package shader
 
 import (
+	"fmt"
 	"github.com/faiface/mainthread"
 	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/wieku/danser-go/framework/assets"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 type Type uint32
@@ -31,11 +37,19 @@ type Source struct {
 	success bool
 	log     string
 	srcType Type
+	files   []string
 }
 
 func NewSource(source string, srcType Type) *Source {
+	return NewSourceNamed(source, srcType)
+}
+
+// NewSourceNamed compiles the source like NewSource. Files are names of GLSL source strings (usually file paths,
+// as returned by Preprocess) shown in compile errors.
+func NewSourceNamed(source string, srcType Type, files ...string) *Source {
 	src := new(Source)
 	src.srcType = srcType
+	src.files = files
 
 	src.handle = gl.CreateShader(uint32(src.srcType))
 
@@ -64,6 +78,81 @@ func NewSource(source string, srcType Type) *Source {
 	return src
 }
 
+// Err returns nil if the source compiled successfully, otherwise the compile log with lines prefixed by file name and line number.
+func (src *Source) Err() error {
+	if src.success {
+		return nil
+	}
+
+	if len(src.files) == 0 {
+		return fmt.Errorf("failed to build %s: %s", src.srcType.Name(), src.log)
+	}
+
+	return fmt.Errorf("failed to build %s %s:\n%s", src.srcType.Name(), src.files[0], formatLog(src.files, src.log))
+}
+
+// LoadSource reads the shader from assets, resolves #include directives and compiles it.
+// Type is taken from the extension: .vsh, .gsh or .fsh.
+func LoadSource(file string) (*Source, error) {
+	var srcType Type
+
+	switch strings.ToLower(path.Ext(file)) {
+	case ".vsh":
+		srcType = Vertex
+	case ".gsh":
+		srcType = Geometry
+	case ".fsh":
+		srcType = Fragment
+	default:
+		return nil, fmt.Errorf("unknown shader type: %s", file)
+	}
+
+	source, files, err := Preprocess(file, assets.GetString)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSourceNamed(source, srcType, files...), nil
+}
+
+// Drivers differ in log format: "0(12) : error ..." (NVIDIA), "0:12(5): error: ..." (Mesa), "ERROR: 0:12: ..." (AMD, Intel)
+var logLine = regexp.MustCompile(`^(?:(ERROR|WARNING): )?(\d+)(?::(\d+)|\((\d+)\))(?:\((\d+)\))?\s*:\s*(.*)$`)
+
+func formatLog(files []string, log string) string {
+	var builder strings.Builder
+
+	for _, line := range strings.Split(strings.TrimRight(log, "\x00\r\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if match := logLine.FindStringSubmatch(line); match != nil {
+			name := match[2]
+			if index, err := strconv.Atoi(match[2]); err == nil && index < len(files) {
+				name = files[index]
+			}
+
+			location := name + ":" + match[3] + match[4]
+			if match[5] != "" {
+				location += ":" + match[5]
+			}
+
+			message := match[6]
+			if match[1] != "" {
+				message = strings.ToLower(match[1]) + ": " + message
+			}
+
+			line = location + ": " + message
+		}
+
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
 func (src *Source) Dispose() {
 	mainthread.CallNonBlock(func() {
 		gl.DeleteShader(src.handle)
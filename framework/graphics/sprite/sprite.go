@@ -351,6 +351,13 @@ func (sprite *Sprite) SetCutOrigin(origin vector.Vector2d) {
 	sprite.cutOrigin = origin
 }
 
+// SetTextures replaces sprite's frames, transformations and timing stay untouched.
+func (sprite *Sprite) SetTextures(textures []*texture.TextureRegion, frameDelay float64) {
+	sprite.Textures = textures
+	sprite.frameDelay = frameDelay
+	sprite.currentFrame = 0
+}
+
 func (sprite *Sprite) SetAdditive(on bool) {
 	sprite.additive = on
 }
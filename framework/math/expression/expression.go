@@ -0,0 +1,151 @@
+// Package expression implements a small typed expression language working on scalars and 2D vectors.
+//
+// Supported syntax:
+//
+//	numbers: 1, 0.5, 1e-3
+//	vectors: [x, y], component access: v.x, v.y
+//	arithmetic: + - * / % ^ (power), unary -
+//	comparisons: < <= > >= == != (result is 1 or 0)
+//	logic: && || ! (non-zero is true)
+//	conditionals: cond ? a : b, if(cond, a, b)
+//	function calls: sin(x), lerp(a, b, t), see functions.go
+//
+// Types are checked when the expression is compiled, so evaluation can't fail.
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Type int
+
+const (
+	Scalar = Type(iota)
+	Vector
+)
+
+func (t Type) String() string {
+	if t == Vector {
+		return "vector"
+	}
+
+	return "scalar"
+}
+
+// Value holds either a scalar (in X) or a vector.
+type Value struct {
+	X, Y float64
+}
+
+func NewScalar(x float64) Value {
+	return Value{X: x}
+}
+
+func NewVector(x, y float64) Value {
+	return Value{X: x, Y: y}
+}
+
+func boolValue(b bool) Value {
+	if b {
+		return Value{X: 1}
+	}
+
+	return Value{}
+}
+
+// Error describes a problem found during compilation, Column is a 0-based character index in Source.
+type Error struct {
+	Source  string
+	Column  int
+	Message string
+}
+
+func newError(source string, column int, message string) *Error {
+	return &Error{Source: source, Column: column, Message: message}
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s at column %d\n\t%s\n\t%s^", err.Message, err.Column+1, err.Source, strings.Repeat(" ", err.Column))
+}
+
+// Environment declares variables available to expressions and holds their current values.
+type Environment struct {
+	slots  map[string]int
+	types  []Type
+	values []Value
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{slots: make(map[string]int)}
+}
+
+// Declare adds a variable and returns its slot used by Set. Declaring an existing variable again returns its old slot.
+func (env *Environment) Declare(name string, t Type) int {
+	if slot, ok := env.slots[name]; ok {
+		env.types[slot] = t
+		return slot
+	}
+
+	env.slots[name] = len(env.values)
+	env.types = append(env.types, t)
+	env.values = append(env.values, Value{})
+
+	return len(env.values) - 1
+}
+
+func (env *Environment) Set(slot int, value Value) {
+	env.values[slot] = value
+}
+
+func (env *Environment) SetScalar(slot int, x float64) {
+	env.values[slot] = Value{X: x}
+}
+
+func (env *Environment) SetVector(slot int, x, y float64) {
+	env.values[slot] = Value{X: x, Y: y}
+}
+
+func (env *Environment) Get(slot int) Value {
+	return env.values[slot]
+}
+
+type evaluator func(values []Value) Value
+
+// Expression is a compiled expression bound to the Environment it was compiled with.
+type Expression struct {
+	source string
+	typ    Type
+	eval   evaluator
+	env    *Environment
+}
+
+// Compile parses and type-checks source. Only variables already declared in env can be used.
+func Compile(source string, env *Environment) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{source: source, tokens: tokens, env: env}
+
+	n, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{source: source, typ: n.typ, eval: n.eval, env: env}, nil
+}
+
+func (expr *Expression) Type() Type {
+	return expr.typ
+}
+
+func (expr *Expression) String() string {
+	return expr.source
+}
+
+// Evaluate computes the expression using current values of the Environment.
+func (expr *Expression) Evaluate() Value {
+	return expr.eval(expr.env.values)
+}
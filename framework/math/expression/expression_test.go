@@ -0,0 +1,166 @@
+package expression
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func testEnvironment() *Environment {
+	env := NewEnvironment()
+	env.SetScalar(env.Declare("t", Scalar), 2)
+	env.SetVector(env.Declare("pos", Vector), 3, 4)
+
+	return env
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected Value
+	}{
+		// precedence and associativity
+		{"-2^2", NewScalar(-4)},
+		{"(-2)^2", NewScalar(4)},
+		{"2^3^2", NewScalar(512)},
+		{"2^-1", NewScalar(0.5)},
+		{"-7%3", NewScalar(2)},
+		{"7%-3", NewScalar(-2)},
+		{"-(7%3)", NewScalar(-1)},
+		{"1+2*3", NewScalar(7)},
+		{"(1+2)*3", NewScalar(9)},
+		{"8/4/2", NewScalar(1)},
+		{"10-4-3", NewScalar(3)},
+		{"2*3^2", NewScalar(18)},
+		{"1 + 2 < 4", NewScalar(1)},
+		{"1 < 2 == 1", NewScalar(1)},
+		{"0 || 1 && 0", NewScalar(0)},
+		{"!0 + 1", NewScalar(2)},
+		{"1 ? 0 ? 5 : 6 : 7", NewScalar(6)},
+
+		// variables, constants and vectors
+		{"t * pi", NewScalar(2 * math.Pi)},
+		{"pos.x + pos.y", NewScalar(7)},
+		{"pos * t", NewVector(6, 8)},
+		{"t * pos - [1, 1]", NewVector(5, 7)},
+		{"pos / 2", NewVector(1.5, 2)},
+		{"-pos", NewVector(-3, -4)},
+		{"pos == [3, 4]", NewScalar(1)},
+		{"[t, t ^ 2].y", NewScalar(4)},
+
+		// functions
+		{"length(pos)", NewScalar(5)},
+		{"max(t, 3)", NewScalar(3)},
+		{"if(t > 1, pos, [0, 0])", NewVector(3, 4)},
+		{"lerp(0, 10, 0.25)", NewScalar(2.5)},
+		{"clamp(t, 0, 1)", NewScalar(1)},
+	}
+
+	for _, test := range tests {
+		expr, err := Compile(test.source, testEnvironment())
+		if err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+
+		if actual := expr.Evaluate(); math.Abs(actual.X-test.expected.X) > 1e-9 || math.Abs(actual.Y-test.expected.Y) > 1e-9 {
+			t.Errorf("%s = %v, expected %v", test.source, actual, test.expected)
+		}
+	}
+}
+
+// Division by zero follows IEEE 754 instead of failing, movers have to handle infinities themselves
+func TestDivisionByZero(t *testing.T) {
+	tests := []struct {
+		source string
+		check  func(float64) bool
+	}{
+		{"1/0", func(x float64) bool { return math.IsInf(x, 1) }},
+		{"-1/0", func(x float64) bool { return math.IsInf(x, -1) }},
+		{"0/0", math.IsNaN},
+		{"t/(t-2)", func(x float64) bool { return math.IsInf(x, 1) }},
+		{"5%0", math.IsNaN},
+		{"mod(5, 0)", math.IsNaN},
+		{"(pos / 0).x", func(x float64) bool { return math.IsInf(x, 1) }},
+	}
+
+	for _, test := range tests {
+		expr, err := Compile(test.source, testEnvironment())
+		if err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+
+		if actual := expr.Evaluate().X; !test.check(actual) {
+			t.Errorf("%s = %v", test.source, actual)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		column  int
+		message string
+	}{
+		// types
+		{"1 + pos", 2, "operator \"+\" can't be used with scalar and vector"},
+		{"t * pos ^ 2", 8, "operator \"^\" can't be used with vector and scalar"},
+		{"2 / pos", 2, "operator \"/\" can't be used with scalar and vector"},
+		{"pos < 1", 4, "operator \"<\" can't be used with vector and scalar"},
+		{"t.x", 1, "component access requires a vector"},
+		{"pos.z", 4, "unknown vector component"},
+		{"!pos", 0, "operator \"!\" requires a scalar"},
+		{"pos ? 1 : 2", 0, "condition has to be a scalar"},
+		{"t ? 1 : pos", 8, "both branches of a conditional have to be of the same type"},
+		{"[pos, 1]", 0, "vector components have to be scalars"},
+		{"sin(pos)", 0, "function \"sin\" can't be called with sin(vector)"},
+		{"t + max(1)", 4, "function \"max\" can't be called with max(scalar)"},
+		{"if(1, 2)", 0, "function \"if\" takes 3 arguments"},
+
+		// unknown identifiers and functions
+		{"x + 1", 0, "unknown variable \"x\""},
+		{"t * speed", 4, "unknown variable \"speed\""},
+		{"sine(t)", 0, "unknown function \"sine\""},
+		{"1 + foo(t, 2)", 4, "unknown function \"foo\""},
+
+		// syntax
+		{"", 0, "expression is empty"},
+		{"1 +", 3, "unexpected"},
+		{"(1 + 2", 6, "expected \")\""},
+		{"1 2", 2, "unexpected"},
+		{"t $ 2", 2, "unexpected character '$'"},
+	}
+
+	for _, test := range tests {
+		_, err := Compile(test.source, testEnvironment())
+		if err == nil {
+			t.Errorf("%s: expected an error", test.source)
+			continue
+		}
+
+		var exprErr *Error
+		if !errors.As(err, &exprErr) {
+			t.Errorf("%s: expected *Error, got %T", test.source, err)
+			continue
+		}
+
+		if exprErr.Column != test.column || !strings.Contains(exprErr.Message, test.message) {
+			t.Errorf("%s: got \"%s\" at column %d, expected \"%s\" at column %d", test.source, exprErr.Message, exprErr.Column, test.message, test.column)
+		}
+	}
+}
+
+func TestErrorString(t *testing.T) {
+	_, err := Compile("t + pos", testEnvironment())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	expected := "operator \"+\" can't be used with scalar and vector at column 3\n\tt + pos\n\t  ^"
+
+	if err.Error() != expected {
+		t.Errorf("got:\n%s\nexpected:\n%s", err.Error(), expected)
+	}
+}
@@ -0,0 +1,204 @@
+package expression
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+var constants = map[string]float64{
+	"pi":  math.Pi,
+	"tau": 2 * math.Pi,
+	"e":   math.E,
+}
+
+type function struct {
+	args []Type
+	ret  Type
+	fn   func(args []Value) Value
+}
+
+func (f function) matches(types []Type) bool {
+	if len(f.args) != len(types) {
+		return false
+	}
+
+	for i, t := range types {
+		if f.args[i] != t {
+			return false
+		}
+	}
+
+	return true
+}
+
+func scalar1(f func(x float64) float64) function {
+	return function{[]Type{Scalar}, Scalar, func(a []Value) Value { return Value{X: f(a[0].X)} }}
+}
+
+func scalar2(f func(x, y float64) float64) function {
+	return function{[]Type{Scalar, Scalar}, Scalar, func(a []Value) Value { return Value{X: f(a[0].X, a[1].X)} }}
+}
+
+func scalar3(f func(x, y, z float64) float64) function {
+	return function{[]Type{Scalar, Scalar, Scalar}, Scalar, func(a []Value) Value { return Value{X: f(a[0].X, a[1].X, a[2].X)} }}
+}
+
+func mod(a, b float64) float64 {
+	r := math.Mod(a, b)
+	if r != 0 && (r < 0) != (b < 0) {
+		r += b
+	}
+
+	return r
+}
+
+func clamp(x, min, max float64) float64 {
+	return math.Max(min, math.Min(max, x))
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func length(v Value) float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y)
+}
+
+var functions = map[string][]function{
+	"sin":   {scalar1(math.Sin)},
+	"cos":   {scalar1(math.Cos)},
+	"tan":   {scalar1(math.Tan)},
+	"asin":  {scalar1(math.Asin)},
+	"acos":  {scalar1(math.Acos)},
+	"atan":  {scalar1(math.Atan)},
+	"atan2": {scalar2(math.Atan2)},
+	"sqrt":  {scalar1(math.Sqrt)},
+	"exp":   {scalar1(math.Exp)},
+	"log":   {scalar1(math.Log)},
+	"log2":  {scalar1(math.Log2)},
+	"pow":   {scalar2(math.Pow)},
+	"floor": {scalar1(math.Floor)},
+	"ceil":  {scalar1(math.Ceil)},
+	"round": {scalar1(math.Round)},
+	"fract": {scalar1(func(x float64) float64 { return x - math.Floor(x) })},
+	"mod":   {scalar2(mod)},
+	"sign": {scalar1(func(x float64) float64 {
+		if x == 0 {
+			return 0
+		}
+
+		return math.Copysign(1, x)
+	})},
+	"min":   {scalar2(math.Min)},
+	"max":   {scalar2(math.Max)},
+	"clamp": {scalar3(clamp)},
+	"smoothstep": {scalar3(func(e0, e1, x float64) float64 {
+		t := clamp((x-e0)/(e1-e0), 0, 1)
+		return t * t * (3 - 2*t)
+	})},
+	"abs": {
+		scalar1(math.Abs),
+		{[]Type{Vector}, Vector, func(a []Value) Value { return Value{math.Abs(a[0].X), math.Abs(a[0].Y)} }},
+	},
+	"lerp": {
+		scalar3(lerp),
+		{[]Type{Vector, Vector, Scalar}, Vector, func(a []Value) Value {
+			return Value{lerp(a[0].X, a[1].X, a[2].X), lerp(a[0].Y, a[1].Y, a[2].X)}
+		}},
+	},
+	"vec": {
+		{[]Type{Scalar, Scalar}, Vector, func(a []Value) Value { return Value{a[0].X, a[1].X} }},
+	},
+	"polar": {
+		{[]Type{Scalar, Scalar}, Vector, func(a []Value) Value {
+			return Value{math.Cos(a[0].X) * a[1].X, math.Sin(a[0].X) * a[1].X}
+		}},
+	},
+	"length": {
+		{[]Type{Vector}, Scalar, func(a []Value) Value { return Value{X: length(a[0])} }},
+	},
+	"dist": {
+		{[]Type{Vector, Vector}, Scalar, func(a []Value) Value { return Value{X: length(Value{a[0].X - a[1].X, a[0].Y - a[1].Y})} }},
+	},
+	"normalize": {
+		{[]Type{Vector}, Vector, func(a []Value) Value {
+			l := length(a[0])
+			if l == 0 {
+				return Value{}
+			}
+
+			return Value{a[0].X / l, a[0].Y / l}
+		}},
+	},
+	"dot": {
+		{[]Type{Vector, Vector}, Scalar, func(a []Value) Value { return Value{X: a[0].X*a[1].X + a[0].Y*a[1].Y} }},
+	},
+	"cross": {
+		{[]Type{Vector, Vector}, Scalar, func(a []Value) Value { return Value{X: a[0].X*a[1].Y - a[0].Y*a[1].X} }},
+	},
+	"angle": {
+		{[]Type{Vector}, Scalar, func(a []Value) Value { return Value{X: math.Atan2(a[0].Y, a[0].X)} }},
+		{[]Type{Vector, Vector}, Scalar, func(a []Value) Value { return Value{X: math.Atan2(a[1].Y-a[0].Y, a[1].X-a[0].X)} }},
+	},
+	"rotate": {
+		{[]Type{Vector, Scalar}, Vector, func(a []Value) Value {
+			sin, cos := math.Sincos(a[1].X)
+			return Value{a[0].X*cos - a[0].Y*sin, a[0].X*sin + a[0].Y*cos}
+		}},
+	},
+	"perp": {
+		{[]Type{Vector}, Vector, func(a []Value) Value { return Value{-a[0].Y, a[0].X} }},
+	},
+	"bezier": {
+		{[]Type{Vector, Vector, Vector, Scalar}, Vector, func(a []Value) Value {
+			t := a[3].X
+			u := 1 - t
+
+			return Value{
+				u*u*a[0].X + 2*u*t*a[1].X + t*t*a[2].X,
+				u*u*a[0].Y + 2*u*t*a[1].Y + t*t*a[2].Y,
+			}
+		}},
+		{[]Type{Vector, Vector, Vector, Vector, Scalar}, Vector, func(a []Value) Value {
+			t := a[4].X
+			u := 1 - t
+
+			return Value{
+				u*u*u*a[0].X + 3*u*u*t*a[1].X + 3*u*t*t*a[2].X + t*t*t*a[3].X,
+				u*u*u*a[0].Y + 3*u*u*t*a[1].Y + 3*u*t*t*a[2].Y + t*t*t*a[3].Y,
+			}
+		}},
+	},
+}
+
+func signature(name string, types []Type) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = t.String()
+	}
+
+	return name + "(" + strings.Join(parts, ", ") + ")"
+}
+
+func signatures(name string, overloads []function) string {
+	result := make([]string, len(overloads))
+	for i, f := range overloads {
+		result[i] = signature(name, f.args)
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// Functions returns sorted names of all built-in functions.
+func Functions() []string {
+	names := []string{"if"}
+
+	for name := range functions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,104 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF = tokenKind(iota)
+	tokenNumber
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number float64
+	column int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return "\"" + t.text + "\""
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "+", "-", "*", "/", "%", "^", "(", ")", "[", "]", ",", "?", ":", "<", ">", "!", "."}
+
+func tokenize(source string) ([]token, error) {
+	tokens := make([]token, 0)
+
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for i = j; i < len(runes) && unicode.IsDigit(runes[i]); i++ {
+					}
+				}
+			}
+
+			text := string(runes[start:i])
+
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, newError(source, start, fmt.Sprintf("invalid number \"%s\"", text))
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: text, number: value, column: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), column: start})
+		default:
+			found := false
+
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, column: i})
+					i += len([]rune(op))
+					found = true
+
+					break
+				}
+			}
+
+			if !found {
+				return nil, newError(source, i, fmt.Sprintf("unexpected character '%c'", r))
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, column: len(runes)})
+
+	return tokens, nil
+}
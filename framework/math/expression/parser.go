@@ -0,0 +1,514 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+)
+
+type node struct {
+	typ    Type
+	eval   evaluator
+	column int
+}
+
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+	env    *Environment
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		t := p.peek()
+		return newError(p.source, t.column, fmt.Sprintf("expected \"%s\", got %s", op, t))
+	}
+
+	return nil
+}
+
+func (p *parser) errorf(column int, format string, args ...interface{}) error {
+	return newError(p.source, column, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parse() (*node, error) {
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(0, "expression is empty")
+	}
+
+	n, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t.column, "unexpected %s", t)
+	}
+
+	return n, nil
+}
+
+func (p *parser) parseTernary() (*node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.accept("?") {
+		return cond, nil
+	}
+
+	a, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.expect(":"); err != nil {
+		return nil, err
+	}
+
+	b, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.conditional(cond, a, b)
+}
+
+func (p *parser) conditional(cond, a, b *node) (*node, error) {
+	if cond.typ != Scalar {
+		return nil, p.errorf(cond.column, "condition has to be a scalar, got a vector")
+	}
+
+	if a.typ != b.typ {
+		return nil, p.errorf(b.column, "both branches of a conditional have to be of the same type, got %s and %s", a.typ, b.typ)
+	}
+
+	ce, ae, be := cond.eval, a.eval, b.eval
+
+	return &node{typ: a.typ, column: cond.column, eval: func(v []Value) Value {
+		if ce(v).X != 0 {
+			return ae(v)
+		}
+
+		return be(v)
+	}}, nil
+}
+
+// Binary operators from the lowest precedence.
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (*node, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+
+		matched := false
+
+		if t.kind == tokenOperator {
+			for _, op := range binaryLevels[level] {
+				if t.text == op {
+					matched = true
+					break
+				}
+			}
+		}
+
+		if !matched {
+			return left, nil
+		}
+
+		p.next()
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if left, err = p.binary(t, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseUnary() (*node, error) {
+	t := p.peek()
+
+	if p.accept("-") {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		e := n.eval
+
+		return &node{typ: n.typ, column: t.column, eval: func(v []Value) Value {
+			r := e(v)
+			return Value{-r.X, -r.Y}
+		}}, nil
+	}
+
+	if p.accept("+") {
+		return p.parseUnary()
+	}
+
+	if p.accept("!") {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		if n.typ != Scalar {
+			return nil, p.errorf(t.column, "operator \"!\" requires a scalar, got a vector")
+		}
+
+		e := n.eval
+
+		return &node{typ: Scalar, column: t.column, eval: func(v []Value) Value {
+			return boolValue(e(v).X == 0)
+		}}, nil
+	}
+
+	return p.parsePower()
+}
+
+// parsePower handles the right-associative "^" which binds tighter than unary minus, so -2^2 = -4.
+func (p *parser) parsePower() (*node, error) {
+	base, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+
+	if !p.accept("^") {
+		return base, nil
+	}
+
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.binary(t, base, exponent)
+}
+
+func (p *parser) parsePostfix() (*node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+
+		if !p.accept(".") {
+			return n, nil
+		}
+
+		field := p.next()
+
+		if n.typ != Vector {
+			return nil, p.errorf(t.column, "component access requires a vector, got a scalar")
+		}
+
+		e := n.eval
+
+		switch field.text {
+		case "x":
+			n = &node{typ: Scalar, column: n.column, eval: func(v []Value) Value {
+				return Value{X: e(v).X}
+			}}
+		case "y":
+			n = &node{typ: Scalar, column: n.column, eval: func(v []Value) Value {
+				return Value{X: e(v).Y}
+			}}
+		default:
+			return nil, p.errorf(field.column, "unknown vector component %s, expected \"x\" or \"y\"", field)
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (*node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		value := Value{X: t.number}
+		return &node{typ: Scalar, column: t.column, eval: func([]Value) Value { return value }}, nil
+	case tokenIdent:
+		if p.accept("(") {
+			return p.parseCall(t)
+		}
+
+		if slot, ok := p.env.slots[t.text]; ok {
+			return &node{typ: p.env.types[slot], column: t.column, eval: func(v []Value) Value { return v[slot] }}, nil
+		}
+
+		if c, ok := constants[t.text]; ok {
+			return &node{typ: Scalar, column: t.column, eval: func([]Value) Value { return Value{X: c} }}, nil
+		}
+
+		return nil, p.errorf(t.column, "unknown variable \"%s\"", t.text)
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			n, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+
+			return n, nil
+		case "[":
+			x, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+
+			if err = p.expect(","); err != nil {
+				return nil, err
+			}
+
+			y, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+
+			if err = p.expect("]"); err != nil {
+				return nil, err
+			}
+
+			if x.typ != Scalar || y.typ != Scalar {
+				return nil, p.errorf(t.column, "vector components have to be scalars")
+			}
+
+			xe, ye := x.eval, y.eval
+
+			return &node{typ: Vector, column: t.column, eval: func(v []Value) Value {
+				return Value{xe(v).X, ye(v).X}
+			}}, nil
+		}
+	}
+
+	return nil, p.errorf(t.column, "unexpected %s", t)
+}
+
+func (p *parser) parseCall(name token) (*node, error) {
+	args := make([]*node, 0)
+
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, arg)
+
+			if p.accept(")") {
+				break
+			}
+
+			if err = p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if name.text == "if" {
+		if len(args) != 3 {
+			return nil, p.errorf(name.column, "function \"if\" takes 3 arguments, got %d", len(args))
+		}
+
+		return p.conditional(args[0], args[1], args[2])
+	}
+
+	overloads, ok := functions[name.text]
+	if !ok {
+		return nil, p.errorf(name.column, "unknown function \"%s\"", name.text)
+	}
+
+	types := make([]Type, len(args))
+	for i, a := range args {
+		types[i] = a.typ
+	}
+
+	for _, f := range overloads {
+		if !f.matches(types) {
+			continue
+		}
+
+		evals := make([]evaluator, len(args))
+		for i, a := range args {
+			evals[i] = a.eval
+		}
+
+		fn := f.fn
+
+		return &node{typ: f.ret, column: name.column, eval: func(v []Value) Value {
+			values := make([]Value, len(evals))
+			for i, e := range evals {
+				values[i] = e(v)
+			}
+
+			return fn(values)
+		}}, nil
+	}
+
+	return nil, p.errorf(name.column, "function \"%s\" can't be called with %s, accepted: %s", name.text, signature(name.text, types), signatures(name.text, overloads))
+}
+
+func (p *parser) binary(op token, left, right *node) (*node, error) {
+	le, re := left.eval, right.eval
+	lt, rt := left.typ, right.typ
+
+	bothScalar := lt == Scalar && rt == Scalar
+
+	mismatch := func() error {
+		return p.errorf(op.column, "operator \"%s\" can't be used with %s and %s", op.text, lt, rt)
+	}
+
+	scalarOp := func(f func(a, b float64) float64) (*node, error) {
+		if !bothScalar {
+			return nil, mismatch()
+		}
+
+		return &node{typ: Scalar, column: left.column, eval: func(v []Value) Value {
+			return Value{X: f(le(v).X, re(v).X)}
+		}}, nil
+	}
+
+	switch op.text {
+	case "+", "-":
+		if lt != rt {
+			return nil, mismatch()
+		}
+
+		sign := 1.0
+		if op.text == "-" {
+			sign = -1
+		}
+
+		return &node{typ: lt, column: left.column, eval: func(v []Value) Value {
+			a, b := le(v), re(v)
+			return Value{a.X + sign*b.X, a.Y + sign*b.Y}
+		}}, nil
+	case "*":
+		switch {
+		case bothScalar:
+			return scalarOp(func(a, b float64) float64 { return a * b })
+		case lt == Vector && rt == Vector:
+			return &node{typ: Vector, column: left.column, eval: func(v []Value) Value {
+				a, b := le(v), re(v)
+				return Value{a.X * b.X, a.Y * b.Y}
+			}}, nil
+		case lt == Vector:
+			return &node{typ: Vector, column: left.column, eval: func(v []Value) Value {
+				a, b := le(v), re(v).X
+				return Value{a.X * b, a.Y * b}
+			}}, nil
+		default:
+			return &node{typ: Vector, column: left.column, eval: func(v []Value) Value {
+				a, b := le(v).X, re(v)
+				return Value{a * b.X, a * b.Y}
+			}}, nil
+		}
+	case "/":
+		switch {
+		case bothScalar:
+			return scalarOp(func(a, b float64) float64 { return a / b })
+		case lt == Vector && rt == Vector:
+			return &node{typ: Vector, column: left.column, eval: func(v []Value) Value {
+				a, b := le(v), re(v)
+				return Value{a.X / b.X, a.Y / b.Y}
+			}}, nil
+		case lt == Vector:
+			return &node{typ: Vector, column: left.column, eval: func(v []Value) Value {
+				a, b := le(v), re(v).X
+				return Value{a.X / b, a.Y / b}
+			}}, nil
+		default:
+			return nil, mismatch()
+		}
+	case "%":
+		return scalarOp(mod)
+	case "^":
+		return scalarOp(math.Pow)
+	case "<":
+		return scalarOp(func(a, b float64) float64 { return boolValue(a < b).X })
+	case "<=":
+		return scalarOp(func(a, b float64) float64 { return boolValue(a <= b).X })
+	case ">":
+		return scalarOp(func(a, b float64) float64 { return boolValue(a > b).X })
+	case ">=":
+		return scalarOp(func(a, b float64) float64 { return boolValue(a >= b).X })
+	case "==", "!=":
+		if lt != rt {
+			return nil, mismatch()
+		}
+
+		equal := op.text == "=="
+
+		return &node{typ: Scalar, column: left.column, eval: func(v []Value) Value {
+			return boolValue((le(v) == re(v)) == equal)
+		}}, nil
+	case "&&":
+		if !bothScalar {
+			return nil, mismatch()
+		}
+
+		return &node{typ: Scalar, column: left.column, eval: func(v []Value) Value {
+			return boolValue(le(v).X != 0 && re(v).X != 0)
+		}}, nil
+	case "||":
+		if !bothScalar {
+			return nil, mismatch()
+		}
+
+		return &node{typ: Scalar, column: left.column, eval: func(v []Value) Value {
+			return boolValue(le(v).X != 0 || re(v).X != 0)
+		}}, nil
+	}
+
+	return nil, p.errorf(op.column, "unknown operator \"%s\"", op.text)
+}
@@ -5,14 +5,14 @@ go 1.15
 require (
 	github.com/EdlinOrg/prominentcolor v1.0.0
 	github.com/Mempler/rplpa v0.0.0-20190925124510-2150375391cb
-	github.com/bnch/uleb128 v0.0.0-20160221084957-fac1fe18ad59 // indirect
+	github.com/bnch/uleb128 v0.0.0-20160221084957-fac1fe18ad59
 	github.com/faiface/mainthread v0.0.0-20171120011319-8b78f0a41ae3
 	github.com/flesnuk/oppai5 v0.0.0-20201101203857-5d57125c15b1
 	github.com/go-gl/gl v0.0.0-20190320180904-bf2b1f2f34d7
 	github.com/go-gl/glfw v0.0.0-20200707082815-5321531c36a2
 	github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
-	github.com/itchio/lzma v0.0.0-20190703113020-d3e24e3e3d49 // indirect
+	github.com/itchio/lzma v0.0.0-20190703113020-d3e24e3e3d49
 	github.com/karrick/godirwalk v1.16.1
 	github.com/lucasb-eyer/go-colorful v1.0.3 // indirect
 	github.com/mattn/go-sqlite3 v1.14.0
@@ -6,14 +6,18 @@ import (
 	"github.com/faiface/mainthread"
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
 	"github.com/wieku/danser-go/app/audio"
 	"github.com/wieku/danser-go/app/beatmap"
 	camera2 "github.com/wieku/danser-go/app/bmath/camera"
+	"github.com/wieku/danser-go/app/dance"
 	"github.com/wieku/danser-go/app/database"
 	"github.com/wieku/danser-go/app/discord"
 	"github.com/wieku/danser-go/app/graphics/font"
 	"github.com/wieku/danser-go/app/input"
+	"github.com/wieku/danser-go/app/recording"
 	"github.com/wieku/danser-go/app/settings"
+	"github.com/wieku/danser-go/app/skin"
 	"github.com/wieku/danser-go/app/states"
 	"github.com/wieku/danser-go/app/utils"
 	"github.com/wieku/danser-go/build"
@@ -22,16 +26,19 @@ import (
 	"github.com/wieku/danser-go/framework/frame"
 	batch2 "github.com/wieku/danser-go/framework/graphics/batch"
 	"github.com/wieku/danser-go/framework/graphics/blend"
+	"github.com/wieku/danser-go/framework/graphics/shader"
 	"github.com/wieku/danser-go/framework/graphics/viewport"
 	"github.com/wieku/danser-go/framework/math/vector"
 	"github.com/wieku/danser-go/framework/statistic"
 	"image"
 	"io"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 	"unsafe"
 )
 
@@ -48,11 +55,22 @@ var player *states.Player
 var pressed = false
 var pressedM = false
 var pressedP = false
+var pressedS = false
 
-func run() {
+var reloadQueue = make(chan *beatmap.BeatMap, 1)
+
+var recorder *recording.Recorder
+var previewBatch *batch2.QuadBatch
+
+func run() error {
 	var win *glfw.Window
 	var limiter *frame.Limiter
 
+	// set by headless modes (dance analysis, audio mixdown) that finish during initialization
+	done := false
+
+	var recordErr error
+
 	mainthread.Call(func() {
 
 		md5 := flag.String("md5", "", "Specify the beatmap md5 hash. Overrides other beatmap search flags")
@@ -84,6 +102,14 @@ func run() {
 
 		skip := flag.Bool("skip", false, "Skip straight to map's drain time")
 
+		seed := flag.Int64("seed", 0, "Seed of all random choices (slider dance, humanizer, overlays, backgrounds), 0 picks a random one. Renders with the same seed and settings are identical")
+
+		analyze := flag.String("analyze", "", "Run the cursor dance without rendering and write movement metrics of every cursor to the given CSV file")
+
+		mixdown := flag.String("mixdown", "", "Simulate the map without rendering and save music with all hitsounds to the given WAV file. Works without an audio device")
+
+		record := flag.String("record", "", "Render the map with a fixed frame rate to the given output instead of playing it in real time. Audio is saved next to it as a WAV file, see Recording settings")
+
 		flag.Parse()
 
 		closeAfterSettingsLoad := false
@@ -102,13 +128,27 @@ func run() {
 		settings.PITCH = *pitch
 		settings.SKIP = *skip
 		settings.SCRUB = *scrub
+		settings.RECORD = *record != "" || *mixdown != ""
+
+		settings.SEED = *seed
+		if settings.SEED == 0 {
+			settings.SEED = time.Now().UnixNano()
+		}
+
+		log.Println("Session seed:", settings.SEED)
 
 		newSettings := settings.LoadSettings(*settingsVersion)
 
+		if err := dance.ValidateMovers(); err != nil {
+			log.Fatalln("Invalid dance settings:", err)
+		}
+
 		player = nil
 		var beatMap *beatmap.BeatMap = nil
 
 		if !closeAfterSettingsLoad {
+			skin.ImportSkins()
+
 			database.Init()
 			beatmaps := database.LoadBeatmaps()
 
@@ -153,13 +193,40 @@ func run() {
 			if beatMap == nil {
 				log.Println("Beatmap not found, closing...")
 				closeAfterSettingsLoad = true
+			} else if *analyze != "" {
+				analyzeDance(beatMap, *analyze)
+				done = true
+
+				return
 			} else {
 				discord.Connect()
+
+				if settings.General.WatchSongsDir && !settings.RECORD {
+					dir, file := beatMap.Dir, beatMap.File
+
+					database.AddWatchListener(func(event database.BeatmapEvent) {
+						// editors may save by removing and recreating the file, which shows up as Removed followed by Added
+						if (event.Type != database.BeatmapUpdated && event.Type != database.BeatmapAdded) || event.Dir != dir || event.File != file {
+							return
+						}
+
+						select {
+						case reloadQueue <- event.Beatmap:
+						default:
+						}
+					})
+
+					database.StartWatcher()
+				}
 			}
 		}
 
 		assets.Init(build.Stream == "Dev")
 
+		if build.Stream == "Dev" {
+			shader.EnableHotReload()
+		}
+
 		glfw.Init()
 		glfw.WindowHint(glfw.ContextVersionMajor, 3)
 		glfw.WindowHint(glfw.ContextVersionMinor, 3)
@@ -168,6 +235,10 @@ func run() {
 		glfw.WindowHint(glfw.Resizable, glfw.False)
 		glfw.WindowHint(glfw.Samples, int(settings.Graphics.MSAA))
 
+		if *mixdown != "" {
+			glfw.WindowHint(glfw.Visible, glfw.False)
+		}
+
 		var err error
 
 		monitor := glfw.GetPrimaryMonitor()
@@ -285,21 +356,53 @@ func run() {
 		glfw.PollEvents()
 
 		glfw.SwapInterval(0)
-		if settings.Graphics.VSync {
+		if settings.Graphics.VSync && !settings.RECORD {
 			glfw.SwapInterval(1)
 		}
 
-		bass.Init()
+		bass.Init(settings.RECORD)
 		audio.LoadSamples()
 
 		beatmap.ParseTimingPointsAndPauses(beatMap)
 		beatmap.ParseObjects(beatMap)
 		beatMap.LoadCustomSamples()
-		player = states.NewPlayer(beatMap)
+
+		player, err = states.NewPlayer(beatMap)
+		if err != nil {
+			log.Println("Failed to load the map:", err)
+			done = true
+
+			return
+		}
+
 		limiter = frame.NewLimiter(int(settings.Graphics.FPSCap))
+
+		if *mixdown != "" {
+			mixdownAudio(*mixdown)
+			done = true
+
+			return
+		}
+
+		if settings.RECORD {
+			recorder, err = recording.NewRecorder(*record, int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight()))
+			if err != nil {
+				recordErr = err
+				done = true
+
+				return
+			}
+
+			previewBatch = batch2.NewQuadBatchSize(1)
+			previewBatch.SetCamera(mgl32.Ortho(0, float32(settings.Graphics.GetWidth()), 0, float32(settings.Graphics.GetHeight()), -1, 1))
+		}
 	})
 
-	for !win.ShouldClose() {
+	if done {
+		return recordErr
+	}
+
+	for !win.ShouldClose() && (recorder == nil || !player.IsFinished()) && recordErr == nil {
 		mainthread.Call(func() {
 			statistic.Reset()
 			glfw.PollEvents()
@@ -316,7 +419,32 @@ func run() {
 			gl.ClearColor(0, 0, 0, 1)
 			gl.Clear(gl.COLOR_BUFFER_BIT)
 
+			select {
+			case bMap := <-reloadQueue:
+				reloadBeatmap(bMap)
+			default:
+			}
+
 			if player != nil {
+				player.ApplySkinSwitch()
+			}
+
+			if recorder != nil {
+				subframes := recorder.GetSubframes()
+
+				for i := 0; i < subframes; i++ {
+					player.UpdateRecording(1000 / settings.Recording.FPS / float64(subframes))
+
+					recorder.Begin()
+					player.Draw(0)
+
+					if recordErr = recorder.End(); recordErr != nil {
+						break
+					}
+				}
+
+				drawRecordingPreview()
+			} else if player != nil {
 				player.Draw(0)
 			}
 
@@ -365,9 +493,22 @@ func run() {
 				pressedP = false
 			}
 
+			if win.GetKey(glfw.KeyF9) == glfw.Press {
+
+				if !pressedS && player != nil {
+					player.CycleSkin()
+				}
+
+				pressedS = true
+			}
+
+			if win.GetKey(glfw.KeyF9) == glfw.Release {
+				pressedS = false
+			}
+
 			win.SwapBuffers()
 
-			if !settings.Graphics.VSync {
+			if !settings.Graphics.VSync && recorder == nil {
 				limiter.Sync()
 			}
 
@@ -375,6 +516,96 @@ func run() {
 			viewport.ClearStack()
 		})
 	}
+
+	if recorder != nil {
+		mainthread.Call(func() {
+			if recordErr == nil {
+				recordErr = recorder.Finish(player.GetRecordedMusic())
+			}
+
+			// stops the encoder if frames couldn't be written or the output wasn't finished
+			if recordErr != nil {
+				recorder.Abort()
+			}
+		})
+	}
+
+	return recordErr
+}
+
+func mixdownAudio(path string) {
+	log.Println("Rendering audio...")
+
+	bass.StartCapture()
+
+	for !player.IsFinished() {
+		player.UpdateRecording(1000)
+	}
+
+	events := bass.StopCapture()
+
+	if err := recording.SaveAudio(path, player.GetRecordedMusic(), events, player.GetRecordingTime()); err != nil {
+		panic(err)
+	}
+
+	log.Println("Audio saved to", path)
+}
+
+func drawRecordingPreview() {
+	region := recorder.Texture().GetRegion()
+
+	previewBatch.Begin()
+	previewBatch.ResetTransform()
+	previewBatch.SetColor(1, 1, 1, 1)
+	previewBatch.SetTranslation(vector.NewVec2d(settings.Graphics.GetWidthF()/2, settings.Graphics.GetHeightF()/2))
+	previewBatch.DrawTexture(region)
+	previewBatch.End()
+}
+
+func reloadBeatmap(beatMap *beatmap.BeatMap) {
+	log.Println("Beatmap file changed, reloading...")
+
+	if player != nil {
+		settings.SCRUB = math.Max(0, player.GetTime()/1000)
+		player.Dispose()
+	}
+
+	beatmap.ParseTimingPointsAndPauses(beatMap)
+	beatmap.ParseObjects(beatMap)
+	beatMap.LoadCustomSamples()
+
+	var err error
+	if player, err = states.NewPlayer(beatMap); err != nil {
+		log.Println("Failed to reload the map:", err)
+	}
+}
+
+func analyzeDance(beatMap *beatmap.BeatMap, path string) {
+	log.Println("Analyzing cursor dance...")
+
+	beatmap.ParseTimingPointsAndPauses(beatMap)
+	beatmap.ParseObjects(beatMap)
+
+	metrics, err := dance.AnalyzeDance(beatMap)
+	if err != nil {
+		log.Println("Failed to analyze the map:", err)
+		return
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		panic(err)
+	}
+
+	defer file.Close()
+
+	if err = dance.WriteDanceMetricsCSV(file, metrics); err != nil {
+		panic(err)
+	}
+
+	dance.LogDanceMetrics(metrics)
+
+	log.Println("Metrics saved to", path)
 }
 
 func setWorkingDirectory() {
@@ -419,5 +650,16 @@ func main() {
 	setWorkingDirectory()
 	runtime.GOMAXPROCS(runtime.NumCPU())
 	mainthread.CallQueueCap = 100000
-	mainthread.Run(run)
+
+	var runErr error
+
+	mainthread.Run(func() {
+		runErr = run()
+	})
+
+	if runErr != nil {
+		log.Println("Recording failed:", runErr)
+		discord.Disconnect()
+		os.Exit(1)
+	}
 }